@@ -0,0 +1,387 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/gngtwhh/gocui/cursor"
+	"github.com/gngtwhh/gocui/internal/term"
+	"github.com/gngtwhh/gocui/utils"
+	"github.com/gngtwhh/gocui/widget"
+	"github.com/gngtwhh/gocui/window"
+)
+
+// ErrInterrupted is returned by Run when the application is stopped by SIGINT or SIGTERM.
+var ErrInterrupted = errors.New("app: interrupted by signal")
+
+// Handler is a widget that handles events, it can get the focus of the application.
+type Handler interface {
+	widget.Widget
+	// HandleEvent handles an event and reports whether the event is consumed.
+	HandleEvent(ev Event) bool
+}
+
+// Focuser is implemented by handlers that want to know when they get or lose the focus.
+type Focuser interface {
+	SetFocus(focused bool)
+}
+
+// App owns the terminal and runs the event loop.
+// Events go to the focused handler first, then to the function set by OnEvent,
+// then Tab and Shift+Tab move the focus and Ctrl+C stops the application.
+// Redraws are batched: Redraw only marks the screen dirty, the loop renders the root widget into a screen buffer
+// at most once per frame and writes the lines that changed to the terminal.
+type App struct {
+	Property
+	root    widget.Widget
+	buffer  *widget.Buffer
+	handler func(ev Event) bool
+
+	focus   []Handler
+	focused int
+
+	events   chan Event
+	updates  chan func()
+	quit     chan struct{}
+	quitOnce sync.Once
+
+	needRender atomic.Bool
+	needFlush  atomic.Bool
+	last       []string // the lines on the terminal
+}
+
+// New creates an application which renders root over the whole terminal.
+func New(root widget.Widget, mfs ...ModFunc) (*App, error) {
+	if root == nil {
+		return nil, fmt.Errorf("root widget cannot be nil")
+	}
+	p := Property{FramePeriod: time.Second / 30}
+	for _, mf := range mfs {
+		if mf == nil {
+			return nil, fmt.Errorf("modify func cannot be nil")
+		}
+		mf(&p)
+	}
+	if p.FramePeriod <= 0 {
+		p.FramePeriod = time.Second / 30
+	}
+	a := &App{
+		Property: p,
+		root:     root,
+		focused:  -1,
+		events:   make(chan Event, 16),
+		updates:  make(chan func(), 16),
+		quit:     make(chan struct{}),
+	}
+	a.buffer = widget.NewBuffer(0, 0)
+	a.buffer.OnChange = func() {
+		a.needFlush.Store(true)
+	}
+	return a, nil
+}
+
+// OnEvent sets the function to handle the events not consumed by the focused handler.
+// It reports whether the event is consumed.
+func (a *App) OnEvent(f func(ev Event) bool) {
+	a.handler = f
+}
+
+// Focusable appends handlers to the focus chain, the first one gets the focus if nothing is focused.
+func (a *App) Focusable(hs ...Handler) {
+	a.focus = append(a.focus, hs...)
+	if a.focused < 0 && len(a.focus) > 0 {
+		a.setFocus(0)
+	}
+}
+
+// Focus gives the focus to h, which must be in the focus chain.
+func (a *App) Focus(h Handler) {
+	for i, f := range a.focus {
+		if f == h {
+			a.setFocus(i)
+			return
+		}
+	}
+}
+
+// Focused returns the focused handler, or nil.
+func (a *App) Focused() Handler {
+	if a.focused < 0 {
+		return nil
+	}
+	return a.focus[a.focused]
+}
+
+// FocusNext moves the focus to the next handler of the focus chain.
+func (a *App) FocusNext() {
+	if len(a.focus) > 0 {
+		a.setFocus((a.focused + 1) % len(a.focus))
+	}
+}
+
+// FocusPrev moves the focus to the previous handler of the focus chain.
+func (a *App) FocusPrev() {
+	if len(a.focus) > 0 {
+		a.setFocus((a.focused - 1 + len(a.focus)) % len(a.focus))
+	}
+}
+
+func (a *App) setFocus(i int) {
+	if i == a.focused {
+		return
+	}
+	if f, ok := a.Focused().(Focuser); ok {
+		f.SetFocus(false)
+	}
+	a.focused = i
+	if f, ok := a.Focused().(Focuser); ok {
+		f.SetFocus(true)
+	}
+	a.Redraw()
+}
+
+// Redraw schedules a render of the root widget on the next frame. It is safe to call from any goroutine.
+func (a *App) Redraw() {
+	a.needRender.Store(true)
+}
+
+// Update runs f on the loop goroutine and redraws, so that f can change widgets safely.
+func (a *App) Update(f func()) {
+	select {
+	case a.updates <- func() { f(); a.Redraw() }:
+	case <-a.quit:
+	}
+}
+
+// Stop stops the application, Run returns after the last frame is drawn.
+func (a *App) Stop() {
+	a.quitOnce.Do(func() { close(a.quit) })
+}
+
+// Run takes over the terminal and runs the event loop until Stop is called, Ctrl+C is pressed
+// or SIGINT/SIGTERM is received.
+// The terminal is restored when Run returns, including when a handler panics, the panic is then propagated.
+// The restoration is also registered to window.Restore, for panics in other goroutines guarded by window.Guard.
+func (a *App) Run() (err error) {
+	restoreMode, err := window.MakeRaw()
+	if err != nil {
+		return err
+	}
+	a.setup()
+	// the restoration runs once, from Restore or when Run returns, whichever is first
+	var teardownOnce sync.Once
+	teardown := func() { teardownOnce.Do(a.teardown) }
+	// Restore may be called while a panicking goroutine holds the console, so it must not lock it
+	unregister := window.RegisterRestore(teardown)
+	defer func() {
+		unregister()
+		utils.ConsoleMutex.Lock()
+		teardown()
+		utils.ConsoleMutex.Unlock()
+		_ = restoreMode()
+		if p := recover(); p != nil {
+			panic(p)
+		}
+	}()
+
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigs)
+	stopResize := window.OnResize(func(width, height int) {
+		a.post(Event{Type: ResizeEvent, Width: width, Height: height, Time: time.Now()})
+	})
+	defer stopResize()
+	input, err := term.NewReader(os.Stdin)
+	if err != nil {
+		return err
+	}
+	inputDone := make(chan struct{})
+	go func() {
+		defer close(inputDone)
+		a.readInput(input)
+	}()
+	defer func() {
+		a.Stop() // unblocks the posting of an event when a handler panics
+		// the input after Run belongs to the next reader, such as the next Run or a plain read of stdin
+		input.Cancel()
+		<-inputDone
+	}()
+
+	frame := time.NewTicker(a.FramePeriod)
+	defer frame.Stop()
+	var tick <-chan time.Time
+	if a.TickPeriod > 0 {
+		ticker := time.NewTicker(a.TickPeriod)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	a.resize(window.GetConsoleSize())
+	a.draw()
+	for {
+		select {
+		case ev := <-a.events:
+			a.dispatch(ev)
+		case f := <-a.updates:
+			f()
+		case t := <-tick:
+			a.dispatch(Event{Type: TimerEvent, Time: t})
+		case <-frame.C:
+			a.draw()
+		case sig := <-sigs:
+			a.Stop()
+			return fmt.Errorf("%w: %v", ErrInterrupted, sig)
+		case <-a.quit:
+			a.draw()
+			return nil
+		}
+	}
+}
+
+// dispatch sends the event to the focused handler, then to the OnEvent function, then to the default handling.
+func (a *App) dispatch(ev Event) {
+	if ev.Type == ResizeEvent {
+		a.resize(ev.Width, ev.Height)
+	}
+	if h := a.Focused(); h != nil && h.HandleEvent(ev) {
+		return
+	}
+	if a.handler != nil && a.handler(ev) {
+		return
+	}
+	switch {
+	case ev.IsKey(KeyTab):
+		a.FocusNext()
+	case ev.IsKey(KeyBacktab):
+		a.FocusPrev()
+	case ev.IsCtrl('c'):
+		a.Stop()
+	}
+}
+
+// post sends an event to the loop, it is dropped if the application has stopped.
+func (a *App) post(ev Event) {
+	select {
+	case a.events <- ev:
+	case <-a.quit:
+	}
+}
+
+// escWait is how long an ESC waits for the rest of an escape sequence before it is the Esc key.
+const escWait = 30 * time.Millisecond
+
+// readInput reads the keyboard and mouse input and posts the events, until the reader is canceled.
+func (a *App) readInput(r io.Reader) {
+	chunks := make(chan []byte)
+	go func() {
+		defer close(chunks)
+		for {
+			buf := make([]byte, 256)
+			n, err := r.Read(buf)
+			if err != nil {
+				return
+			}
+			chunks <- buf[:n]
+		}
+	}()
+	var pending []byte
+	var escTimeout <-chan time.Time // set while an ESC starts the pending bytes
+	for {
+		select {
+		case b, ok := <-chunks:
+			if !ok {
+				return
+			}
+			pending = append(pending, b...)
+		case <-escTimeout:
+			// nothing completed the sequence in time, the ESC was the key itself
+			a.post(Event{Type: KeyEvent, Key: KeyEsc, Time: time.Now()})
+			pending = pending[1:]
+		}
+		for len(pending) > 0 {
+			ev, n, ok := parseEvent(pending)
+			if n == 0 {
+				break
+			}
+			pending = pending[n:]
+			if ok {
+				a.post(ev)
+			}
+		}
+		escTimeout = nil
+		if len(pending) > 0 && pending[0] == '\033' {
+			escTimeout = time.After(escWait)
+		}
+	}
+}
+
+// resize resizes the screen buffer to the terminal and renders everything again.
+func (a *App) resize(width, height int) {
+	a.buffer.Resize(height, width)
+	a.last = nil
+	utils.ConsoleMutex.Lock()
+	window.ClearScreen()
+	utils.ConsoleMutex.Unlock()
+	a.Redraw()
+}
+
+// draw renders the root widget if needed, then writes the changed lines of the screen buffer to the terminal.
+func (a *App) draw() {
+	if a.needRender.Swap(false) {
+		a.buffer.Clear()
+		a.root.Render(a.buffer.Region())
+	}
+	if !a.needFlush.Swap(false) {
+		return
+	}
+	lines := a.buffer.Lines()
+	out := strings.Builder{}
+	out.WriteString("\033[?2026h") // begin synchronized update, so the terminal shows the frame at once
+	for i, line := range lines {
+		if i < len(a.last) && a.last[i] == line {
+			continue
+		}
+		out.WriteString(cursor.GotoXYSeq(i, 0))
+		out.WriteString(line)
+	}
+	out.WriteString("\033[?2026l")
+	utils.ConsoleMutex.Lock()
+	fmt.Print(out.String())
+	utils.ConsoleMutex.Unlock()
+	a.last = lines
+}
+
+// setup switches to the alternate screen, hides the cursor and enables the mouse if needed.
+func (a *App) setup() {
+	utils.ConsoleMutex.Lock()
+	defer utils.ConsoleMutex.Unlock()
+	if !a.NoAltScreen {
+		window.EnterAltScreen()
+	}
+	cursor.HideCursor()
+	if a.Mouse {
+		fmt.Print("\033[?1000h\033[?1002h\033[?1006h")
+	}
+}
+
+// teardown undoes setup and resets the colors, utils.ConsoleMutex must be held unless called by window.Restore.
+func (a *App) teardown() {
+	if a.Mouse {
+		fmt.Print("\033[?1006l\033[?1002l\033[?1000l")
+	}
+	fmt.Print("\033[0m")
+	cursor.ShowCursor()
+	if !a.NoAltScreen {
+		window.ExitAltScreen()
+	} else {
+		fmt.Print("\r\n")
+	}
+}
@@ -0,0 +1,190 @@
+package app
+
+import (
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Event types
+const (
+	KeyEvent = iota
+	MouseEvent
+	ResizeEvent
+	TimerEvent
+)
+
+// Keys of a KeyEvent
+const (
+	KeyRune      = iota // a printable character, stored in Event.Rune
+	KeyCtrl             // Ctrl with a character, stored in Event.Rune, e.g. Ctrl+C is KeyCtrl with Rune 'c'
+	KeyEnter            // Enter
+	KeyTab              // Tab
+	KeyBacktab          // Shift+Tab
+	KeyBackspace        // Backspace
+	KeyEsc              // Escape
+	KeyUp               // arrow up
+	KeyDown             // arrow down
+	KeyLeft             // arrow left
+	KeyRight            // arrow right
+	KeyHome             // Home
+	KeyEnd              // End
+	KeyPgUp             // Page up
+	KeyPgDn             // Page down
+	KeyInsert           // Insert
+	KeyDelete           // Delete
+	KeyF1               // F1, F2 ~ F12 follow
+)
+
+// Mouse buttons of a MouseEvent
+const (
+	MouseLeft = iota
+	MouseMiddle
+	MouseRight
+	MouseRelease
+	MouseWheelUp
+	MouseWheelDown
+)
+
+// Event is an event handled by the application loop.
+type Event struct {
+	Type int  // KeyEvent, MouseEvent, ResizeEvent or TimerEvent
+	Key  int  // the key of a KeyEvent
+	Rune rune // the character of KeyRune and KeyCtrl
+	Alt  bool // whether Alt was held
+
+	Button int // the button of a MouseEvent
+	X, Y   int // the position of a MouseEvent, X is the row and Y is the column as in cursor.GotoXY
+
+	Width, Height int // the new size of the terminal of a ResizeEvent
+
+	Time time.Time // the time the event happened
+}
+
+// IsKey reports whether the event is the given key.
+func (ev Event) IsKey(key int) bool {
+	return ev.Type == KeyEvent && ev.Key == key
+}
+
+// IsRune reports whether the event is the given printable character.
+func (ev Event) IsRune(r rune) bool {
+	return ev.Type == KeyEvent && ev.Key == KeyRune && ev.Rune == r
+}
+
+// IsCtrl reports whether the event is Ctrl with the given character.
+func (ev Event) IsCtrl(r rune) bool {
+	return ev.Type == KeyEvent && ev.Key == KeyCtrl && ev.Rune == r
+}
+
+// csiKeys are the keys of "\033[<n>~" sequences
+var csiKeys = map[int]int{
+	1: KeyHome, 2: KeyInsert, 3: KeyDelete, 4: KeyEnd, 5: KeyPgUp, 6: KeyPgDn, 7: KeyHome, 8: KeyEnd,
+	11: KeyF1, 12: KeyF1 + 1, 13: KeyF1 + 2, 14: KeyF1 + 3, 15: KeyF1 + 4,
+	17: KeyF1 + 5, 18: KeyF1 + 6, 19: KeyF1 + 7, 20: KeyF1 + 8, 21: KeyF1 + 9, 23: KeyF1 + 10, 24: KeyF1 + 11,
+}
+
+// letterKeys are the keys of "\033[<letter>" and "\033O<letter>" sequences
+var letterKeys = map[byte]int{
+	'A': KeyUp, 'B': KeyDown, 'C': KeyRight, 'D': KeyLeft, 'H': KeyHome, 'F': KeyEnd, 'Z': KeyBacktab,
+	'P': KeyF1, 'Q': KeyF1 + 1, 'R': KeyF1 + 2, 'S': KeyF1 + 3,
+}
+
+// parseEvent parses the first event in b, and returns it with the number of bytes consumed.
+// n is 0 if b does not hold a complete event yet, ok is false if the bytes are not a known event.
+// A lone ESC is incomplete, it is the Esc key only if nothing follows it in time, see readInput.
+func parseEvent(b []byte) (ev Event, n int, ok bool) {
+	ev = Event{Type: KeyEvent, Time: time.Now()}
+	switch c := b[0]; {
+	case c == '\033':
+		if len(b) == 1 {
+			return ev, 0, false
+		}
+		switch b[1] {
+		case '[':
+			return parseCSI(b, ev)
+		case 'O':
+			if len(b) < 3 {
+				return ev, 0, false
+			}
+			ev.Key, ok = letterKeys[b[2]]
+			return ev, 3, ok
+		}
+		ev, n, ok = parseEvent(b[1:]) // Alt with a key
+		ev.Alt = true
+		if n == 0 {
+			return ev, 0, false
+		}
+		return ev, n + 1, ok
+	case c == '\r' || c == '\n':
+		ev.Key = KeyEnter
+	case c == '\t':
+		ev.Key = KeyTab
+	case c == 127 || c == 8:
+		ev.Key = KeyBackspace
+	case c == 0:
+		ev.Key, ev.Rune = KeyCtrl, ' '
+	case c < 27:
+		ev.Key, ev.Rune = KeyCtrl, rune('a'+c-1)
+	case c < 32:
+		ev.Key, ev.Rune = KeyCtrl, rune('\\'+c-28)
+	default:
+		if !utf8.FullRune(b) {
+			return ev, 0, false
+		}
+		r, size := utf8.DecodeRune(b)
+		ev.Key, ev.Rune = KeyRune, r
+		return ev, size, true
+	}
+	return ev, 1, true
+}
+
+// parseCSI parses a "\033[" sequence, including SGR mouse reports "\033[<b;y;xM".
+func parseCSI(b []byte, ev Event) (Event, int, bool) {
+	end := 2
+	for end < len(b) && (b[end] < 0x40 || b[end] > 0x7e) {
+		end++
+	}
+	if end == len(b) {
+		return ev, 0, false
+	}
+	params, final, n := string(b[2:end]), b[end], end+1
+
+	if strings.HasPrefix(params, "<") && (final == 'M' || final == 'm') {
+		f := strings.Split(params[1:], ";")
+		if len(f) != 3 {
+			return ev, n, false
+		}
+		btn, _ := strconv.Atoi(f[0])
+		col, _ := strconv.Atoi(f[1])
+		row, _ := strconv.Atoi(f[2])
+		ev.Type, ev.X, ev.Y = MouseEvent, row-1, col-1
+		ev.Alt = btn&8 != 0
+		switch {
+		case final == 'm':
+			ev.Button = MouseRelease
+		case btn&64 != 0 && btn&1 == 0:
+			ev.Button = MouseWheelUp
+		case btn&64 != 0:
+			ev.Button = MouseWheelDown
+		default:
+			ev.Button = []int{MouseLeft, MouseMiddle, MouseRight, MouseRelease}[btn&3]
+		}
+		return ev, n, true
+	}
+
+	f := strings.Split(params, ";")
+	if len(f) == 2 { // modifiers: 3 is Alt
+		mod, _ := strconv.Atoi(f[1])
+		ev.Alt = (mod-1)&2 != 0
+	}
+	if final == '~' {
+		code, _ := strconv.Atoi(f[0])
+		key, ok := csiKeys[code]
+		ev.Key = key
+		return ev, n, ok
+	}
+	key, ok := letterKeys[final]
+	ev.Key = key
+	return ev, n, ok
+}
@@ -0,0 +1,111 @@
+package app
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"github.com/gngtwhh/gocui/widget"
+)
+
+func TestParseEvent(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want Event // Time is not compared
+		n    int
+		ok   bool
+	}{
+		{"rune", "a", Event{Key: KeyRune, Rune: 'a'}, 1, true},
+		{"utf-8 rune", "é!", Event{Key: KeyRune, Rune: 'é'}, 2, true},
+		{"partial utf-8", "\xc3", Event{}, 0, false},
+		{"enter", "\r", Event{Key: KeyEnter}, 1, true},
+		{"tab", "\t", Event{Key: KeyTab}, 1, true},
+		{"backspace", "\x7f", Event{Key: KeyBackspace}, 1, true},
+		{"ctrl+c", "\x03", Event{Key: KeyCtrl, Rune: 'c'}, 1, true},
+		{"ctrl+space", "\x00", Event{Key: KeyCtrl, Rune: ' '}, 1, true},
+		{"esc alone", "\033", Event{}, 0, false},
+		{"alt+esc incomplete", "\033\033", Event{}, 0, false},
+		{"alt+x", "\033x", Event{Key: KeyRune, Rune: 'x', Alt: true}, 2, true},
+		{"csi up", "\033[A", Event{Key: KeyUp}, 3, true},
+		{"csi backtab", "\033[Z", Event{Key: KeyBacktab}, 3, true},
+		{"csi alt+right", "\033[1;3C", Event{Key: KeyRight, Alt: true}, 6, true},
+		{"csi delete", "\033[3~", Event{Key: KeyDelete}, 4, true},
+		{"csi f5", "\033[15~", Event{Key: KeyF1 + 4}, 5, true},
+		{"csi f12 then rune", "\033[24~q", Event{Key: KeyF1 + 11}, 5, true},
+		{"csi unknown", "\033[99~", Event{}, 5, false},
+		{"csi incomplete", "\033[1;", Event{}, 0, false},
+		{"ss3 f1", "\033OP", Event{Key: KeyF1}, 3, true},
+		{"ss3 home", "\033OH", Event{Key: KeyHome}, 3, true},
+		{"ss3 incomplete", "\033O", Event{}, 0, false},
+		{"mouse left press", "\033[<0;10;5M", Event{Type: MouseEvent, Button: MouseLeft, X: 4, Y: 9}, 10, true},
+		{"mouse right press", "\033[<2;1;1M", Event{Type: MouseEvent, Button: MouseRight}, 9, true},
+		{"mouse release", "\033[<0;3;4m", Event{Type: MouseEvent, Button: MouseRelease, X: 3, Y: 2}, 9, true},
+		{"mouse wheel up", "\033[<64;2;3M", Event{Type: MouseEvent, Button: MouseWheelUp, X: 2, Y: 1}, 10, true},
+		{"mouse wheel down", "\033[<65;2;3M", Event{Type: MouseEvent, Button: MouseWheelDown, X: 2, Y: 1}, 10, true},
+		{"mouse with alt", "\033[<8;1;1M", Event{Type: MouseEvent, Button: MouseLeft, Alt: true}, 9, true},
+		{"mouse malformed", "\033[<0;1M", Event{Type: KeyEvent}, 7, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ev, n, ok := parseEvent([]byte(tt.in))
+			if n != tt.n || ok != tt.ok {
+				t.Fatalf("parseEvent(%q) consumed %d ok %v, want %d ok %v", tt.in, n, ok, tt.n, tt.ok)
+			}
+			if !ok {
+				return
+			}
+			ev.Time = tt.want.Time
+			if ev != tt.want {
+				t.Errorf("parseEvent(%q) = %+v, want %+v", tt.in, ev, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadInput(t *testing.T) {
+	a, _ := New(widget.Text{})
+	r, w := io.Pipe()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		a.readInput(r)
+	}()
+	next := func() Event {
+		select {
+		case ev := <-a.events:
+			return ev
+		case <-time.After(time.Second):
+			t.Fatal("no event")
+			return Event{}
+		}
+	}
+	tests := []struct {
+		name   string
+		writes []string // written escWait/5 apart
+		want   []int
+	}{
+		{"esc after the timeout", []string{"\033"}, []int{KeyEsc}},
+		{"sequence split across reads", []string{"\033", "[A"}, []int{KeyUp}},
+		{"esc then a key", []string{"\033", "", "", "", "", "", "", "x"}, []int{KeyEsc, KeyRune}},
+		{"double esc", []string{"\033\033"}, []int{KeyEsc, KeyEsc}},
+	}
+	for _, tt := range tests {
+		for _, s := range tt.writes {
+			if s != "" {
+				w.Write([]byte(s))
+			}
+			time.Sleep(escWait / 5)
+		}
+		for _, key := range tt.want {
+			if ev := next(); ev.Key != key {
+				t.Errorf("%s: key %d, want %d", tt.name, ev.Key, key)
+			}
+		}
+	}
+	w.Close()
+	<-done
+	if len(a.events) > 0 {
+		t.Errorf("unexpected event %+v", <-a.events)
+	}
+}
@@ -0,0 +1,45 @@
+package app
+
+import "time"
+
+// ModFunc is a function that modifies the Property of the application.
+type ModFunc func(p *Property)
+
+// Property is the property of the application.
+type Property struct {
+	FramePeriod time.Duration // FramePeriod: the minimum interval between two redraws, default 33ms(30 fps)
+	TickPeriod  time.Duration // TickPeriod: the interval of TimerEvent, 0(default) means no timer events
+	Mouse       bool          // Mouse: whether mouse events are reported, default: false
+	NoAltScreen bool          // NoAltScreen: draw on the main screen instead of the alternate screen, default: false
+}
+
+// WithFrameRate sets the maximum number of redraws per second.
+func WithFrameRate(fps int) ModFunc {
+	return func(p *Property) {
+		if fps > 0 {
+			p.FramePeriod = time.Second / time.Duration(fps)
+		}
+	}
+}
+
+// WithTick makes the application send a TimerEvent every period.
+func WithTick(period time.Duration) ModFunc {
+	return func(p *Property) {
+		p.TickPeriod = period
+	}
+}
+
+// WithMouse enables mouse events.
+func WithMouse() ModFunc {
+	return func(p *Property) {
+		p.Mouse = true
+	}
+}
+
+// WithoutAltScreen makes the application draw on the main screen,
+// so what is drawn is kept after the application exits.
+func WithoutAltScreen() ModFunc {
+	return func(p *Property) {
+		p.NoAltScreen = true
+	}
+}
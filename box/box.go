@@ -2,11 +2,9 @@ package box
 
 import (
 	"fmt"
-	"github.com/gngtwhh/gocui/cursor"
 	"github.com/gngtwhh/gocui/font"
-	"github.com/gngtwhh/gocui/utils"
+	"github.com/gngtwhh/gocui/widget"
 	"github.com/gngtwhh/gocui/window"
-	"strings"
 )
 
 /**
@@ -89,9 +87,12 @@ type Property struct {
 	TitlePos   int // title pos (Top/Bottom/Inside x Left/Middle/Right), default TopLeft
 	PosX, PosY int // default pos to be print
 
-	BindPos bool // Whether bind the absolute pos, PosX and PosY are valid only when BindPos is true
+	BindPos    bool // Whether bind the absolute pos, PosX and PosY are valid only when BindPos is true
+	Inline     bool // Whether a box without a position is printed at the line of the cursor instead of (0, 0)
+	FullScreen bool // Whether to print in the full-screen mode, filling the whole window
 }
 
+// Box is a box template, use Print or Panel to draw it.
 type Box struct {
 	Property
 }
@@ -263,26 +264,9 @@ func NewBox(mfs ...ModFunc) (box *Box, err error) {
 	return &Box{p}, nil
 }
 
-func (box *Box) Print(title string, payload []string) {
-	p := box.Property
-	maxLen := 0
-	for _, str := range payload {
-		maxLen = max(maxLen, len(str))
-	}
-	x, y := 0, 0
-	if box.Property.BindPos {
-		x, y = p.PosX, p.PosY
-	}
-	var line string
-	utils.ConsoleMutex.Lock()
-	defer utils.ConsoleMutex.Unlock()
-	// first line
-	line = font.Splice(
-		p.TopLeftColor, p.TopLeft, p.TopColor, strings.Repeat(string(p.Top), maxLen+p.PadX*2), p.TopRightColor,
-		p.TopRight,
-	)
-	cursor.GotoXY(x, y)
-	fmt.Print(line)
-	y++
-
+// Print prints the box with the given title and text payload.
+// If BindPos is true, the box will be printed at (PosX, PosY), otherwise at the line of the cursor if Inline is true,
+// or at (0, 0). A FullScreen box stays in the full-screen mode until exit is called, see Panel.Print.
+func (box *Box) Print(title string, payload []string) (exit func()) {
+	return box.Panel(title, widget.Text{Lines: payload, Color: box.InnerColor}).Print()
 }
@@ -30,9 +30,25 @@ func WithPos(x, y int) ModFunc {
 	}
 }
 
+// WithInline makes a box without a position print at the line of the cursor, in lines reserved there
+// which follow the output when the terminal scrolls, instead of at (0, 0).
+func WithInline() ModFunc {
+	return func(p *Property) {
+		p.Inline = true
+	}
+}
+
 // WithStyle sets the style of the box.
 func WithStyle(s Style) ModFunc {
 	return func(p *Property) {
 		p.Style = s
 	}
 }
+
+// WithFullScreen makes the box print in the full-screen mode of the window package,
+// filling the whole window. The mode is left by the function returned by Print.
+func WithFullScreen() ModFunc {
+	return func(p *Property) {
+		p.FullScreen = true
+	}
+}
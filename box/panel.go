@@ -0,0 +1,192 @@
+package box
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/gngtwhh/gocui/font"
+	"github.com/gngtwhh/gocui/live"
+	"github.com/gngtwhh/gocui/widget"
+	"github.com/gngtwhh/gocui/window"
+)
+
+// Panel is a box bound to a title and the widgets inside it.
+// The widgets are stacked vertically inside the padding of the box.
+// A Panel is itself a widget.Widget, so panels can be nested.
+type Panel struct {
+	box      *Box
+	Title    string
+	Children []widget.Widget
+}
+
+// Panel creates a panel which draws the widgets inside the box with the given title.
+func (box *Box) Panel(title string, children ...widget.Widget) *Panel {
+	return &Panel{box: box, Title: title, Children: children}
+}
+
+// Add appends widgets to the panel.
+func (p *Panel) Add(children ...widget.Widget) *Panel {
+	p.Children = append(p.Children, children...)
+	return p
+}
+
+// Print prints the panel at (PosX, PosY) of the box if BindPos is true, otherwise at the line of the cursor
+// if the box is Inline, or at (0, 0).
+// If the box is FullScreen, it enters the full-screen mode and the panel fills the whole window,
+// until exit is called. exit does nothing for other boxes.
+// An inline panel is printed once, use PrintInline to keep updating the widgets inside it.
+func (p *Panel) Print() (exit func()) {
+	if p.box.FullScreen {
+		window.EnterFullScreen()
+		w, h := window.GetConsoleSize()
+		p.Render(widget.Region{Rows: h, Cols: w})
+		var once sync.Once
+		return func() { once.Do(window.ExitFullScreen) }
+	}
+	if !p.box.BindPos && p.box.Inline {
+		p.PrintInline().Close()
+		return func() {}
+	}
+	x, y := 0, 0
+	if p.box.BindPos {
+		x, y = p.box.PosX, p.box.PosY
+	}
+	widget.Print(p, x, y)
+	return func() {}
+}
+
+// PrintInline reserves lines from the line of the cursor and prints the panel there,
+// relative to the cursor so it keeps its place when the terminal scrolls.
+// Widgets inside the panel that update themselves keep being redrawn until the returned region is closed.
+func (p *Panel) PrintInline() *live.Region {
+	r := live.New(0)
+	r.Render(p)
+	return r
+}
+
+// Measure implements widget.Widget
+func (p *Panel) Measure(c widget.Constraints) widget.Size {
+	prop := p.box.Property
+	inner := widget.Constraints{}
+	if c.MaxCols > 0 {
+		inner.MaxCols = max(c.MaxCols-2-prop.PadX*2, 1)
+	}
+	titleRow := 0
+	if prop.TitlePos >= InsideLeft {
+		titleRow = 1
+	}
+	left := -1 // the rows left to the children as in Render, -1 if unbounded
+	if c.MaxRows > 0 {
+		left = max(c.MaxRows-2-prop.PadY*2-titleRow, 0)
+	}
+	var s widget.Size
+	for _, child := range p.Children {
+		if left == 0 {
+			break
+		}
+		if left > 0 {
+			inner.MaxRows = left
+		}
+		cs := child.Measure(inner)
+		s.Rows += cs.Rows
+		s.Cols = max(s.Cols, cs.Cols)
+		if left > 0 {
+			left = max(left-cs.Rows, 0)
+		}
+	}
+	titleWidth := font.Width(p.Title)
+	if titleRow > 0 {
+		s.Rows++
+		s.Cols = max(s.Cols, titleWidth)
+	} else if titleWidth > 0 {
+		s.Cols = max(s.Cols, titleWidth+2-prop.PadX*2)
+	}
+	s.Rows += prop.PadY*2 + 2
+	s.Cols += prop.PadX*2 + 2
+	return c.Fit(s)
+}
+
+// Render implements widget.Widget.
+// The frame is drawn once, then each child is rendered inside the padding,
+// so children that update themselves (like a progress bar) do not redraw the frame.
+func (p *Panel) Render(r widget.Region) {
+	prop := p.box.Property
+	if r.Rows < 2 || r.Cols < 2 {
+		return
+	}
+	innerCols := max(r.Cols-2-prop.PadX*2, 0)
+	title := font.Truncate(p.Title, r.Cols-2)
+	left := font.Splice(prop.LeftColor, prop.Left, font.RESET)
+	right := font.Splice(prop.RightColor, prop.Right, font.RESET)
+
+	r.Line(0, p.borderLine(r.Cols, prop.TopLeft, prop.Top, prop.TopRight,
+		prop.TopLeftColor, prop.TopColor, prop.TopRightColor, title, prop.TitlePos-TopLeft))
+	for i := 1; i < r.Rows-1; i++ {
+		r.Line(i, left+strings.Repeat(" ", r.Cols-2)+right)
+	}
+	r.Line(r.Rows-1, p.borderLine(r.Cols, prop.BottomLeft, prop.Bottom, prop.BottomRight,
+		prop.BottomLeftColor, prop.BottomColor, prop.BottomRightColor, title, prop.TitlePos-BottomLeft))
+
+	inner := r.Sub(1+prop.PadY, 1+prop.PadX, r.Rows-2-prop.PadY*2, innerCols)
+	row := 0
+	if prop.TitlePos >= InsideLeft && prop.TitlePos <= InsideRight {
+		title = font.Truncate(title, innerCols)
+		tw := font.Width(title)
+		inner.Sub(0, offset(innerCols, tw, prop.TitlePos-InsideLeft), 1, tw).
+			Line(0, font.Decorate(title, prop.TitleColor))
+		row++
+	}
+	for _, child := range p.Children {
+		if row >= inner.Rows {
+			break
+		}
+		s := child.Measure(widget.Constraints{MaxRows: inner.Rows - row, MaxCols: innerCols})
+		child.Render(inner.Sub(row, offset(innerCols, s.Cols, alignPos(prop.Align)), s.Rows, s.Cols))
+		row += s.Rows
+	}
+}
+
+// borderLine returns the top or bottom border line, with the title embedded if titlePos is 0(left), 1(mid) or 2(right).
+func (p *Panel) borderLine(cols int, left, mid, right rune, leftColor, midColor, rightColor int,
+	title string, titlePos int) string {
+	inner := cols - 2
+	if titlePos < 0 || titlePos > 2 || title == "" {
+		return font.Splice(leftColor, left, midColor, strings.Repeat(string(mid), inner), rightColor, right, font.RESET)
+	}
+	tw := font.Width(title)
+	before := offset(inner, tw, titlePos)
+	if titlePos == 0 { // keep a border character between the corner and the title
+		before = min(1, inner-tw)
+	} else if titlePos == 2 {
+		before = max(inner-tw-1, 0)
+	}
+	return font.Splice(
+		leftColor, left,
+		midColor, strings.Repeat(string(mid), before),
+		p.box.TitleColor, title,
+		midColor, strings.Repeat(string(mid), inner-before-tw),
+		rightColor, right, font.RESET,
+	)
+}
+
+// offset returns the offset of an item of width w in a space of width total, pos is 0(left), 1(mid) or 2(right).
+func offset(total, w, pos int) int {
+	switch pos {
+	case 1:
+		return max((total-w)/2, 0)
+	case 2:
+		return max(total-w, 0)
+	}
+	return 0
+}
+
+// alignPos converts the content align to the position used by offset.
+func alignPos(align int) int {
+	switch align {
+	case Left:
+		return 0
+	case Right:
+		return 2
+	}
+	return 1
+}
@@ -0,0 +1,73 @@
+package box
+
+import (
+	"testing"
+
+	"github.com/gngtwhh/gocui/widget"
+)
+
+// fill is a widget taking all the rows it is given, or rows if unbounded.
+type fill struct {
+	rows int
+	got  []widget.Constraints
+}
+
+func (f *fill) Measure(c widget.Constraints) widget.Size {
+	f.got = append(f.got, c)
+	s := widget.Size{Rows: f.rows, Cols: 3}
+	if c.MaxRows > 0 {
+		s.Rows = c.MaxRows
+	}
+	return s
+}
+
+func (f *fill) Render(widget.Region) {}
+
+func TestPanelMeasure(t *testing.T) {
+	tests := []struct {
+		name     string
+		titlePos int
+		c        widget.Constraints
+		children int
+		want     widget.Size
+		wantRows []int // the MaxRows given to each child
+	}{
+		{"unbounded", TopLeft, widget.Constraints{}, 2, widget.Size{Rows: 14, Cols: 7}, []int{0, 0}},
+		{"bounded", TopLeft, widget.Constraints{MaxRows: 10}, 1, widget.Size{Rows: 10, Cols: 7}, []int{6}},
+		{"title inside", InsideLeft, widget.Constraints{MaxRows: 10}, 1, widget.Size{Rows: 10, Cols: 7}, []int{5}},
+		{"children share the rows", TopLeft, widget.Constraints{MaxRows: 10}, 2, widget.Size{Rows: 10, Cols: 7}, []int{6}},
+		{"no room", TopLeft, widget.Constraints{MaxRows: 3}, 1, widget.Size{Rows: 3, Cols: 4}, nil},
+	}
+	for _, tt := range tests {
+		b, err := NewBox(WithDefault(), func(p *Property) { p.TitlePos = tt.titlePos })
+		if err != nil {
+			t.Fatal(err)
+		}
+		var children []widget.Widget
+		var fills []*fill
+		for range tt.children {
+			f := &fill{rows: 5}
+			fills = append(fills, f)
+			children = append(children, f)
+		}
+		if got := b.Panel("", children...).Measure(tt.c); got != tt.want {
+			t.Errorf("%s: Measure = %+v, want %+v", tt.name, got, tt.want)
+		}
+		var rows []int
+		for _, f := range fills {
+			for _, c := range f.got {
+				rows = append(rows, c.MaxRows)
+			}
+		}
+		if len(rows) != len(tt.wantRows) {
+			t.Errorf("%s: children measured with MaxRows %v, want %v", tt.name, rows, tt.wantRows)
+			continue
+		}
+		for i := range rows {
+			if rows[i] != tt.wantRows[i] {
+				t.Errorf("%s: children measured with MaxRows %v, want %v", tt.name, rows, tt.wantRows)
+				break
+			}
+		}
+	}
+}
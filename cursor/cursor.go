@@ -2,43 +2,185 @@ package cursor
 
 import (
 	"fmt"
+	"sync"
+	"sync/atomic"
 )
 
-//var (
-//	csi = "\033["
-//)
+// Cursor shapes, the values are the parameters of DECSCUSR
+const (
+	ShapeDefault = iota
+	ShapeBlinkingBlock
+	ShapeBlock
+	ShapeBlinkingUnderline
+	ShapeUnderline
+	ShapeBlinkingBar
+	ShapeBar
+)
+
+var (
+	hidden atomic.Bool  // records whether the cursor has been hidden by HideCursor
+	shape  atomic.Int32 // the shape set by SetShape
+
+	stackMu sync.Mutex
+	stack   []pos // positions pushed by Save
+)
+
+// pos is a saved position, a negative row means the position is saved by the terminal (DECSC)
+type pos struct {
+	row, col int
+}
+
+// GotoXYSeq returns the escape sequence to move the cursor to row x and column y, counted from 0.
+func GotoXYSeq(x, y int) string {
+	return fmt.Sprintf("\033[%d;%dH", x+1, y+1)
+}
+
+// UpSeq returns the escape sequence to move the cursor up by n lines.
+func UpSeq(n int) string {
+	return fmt.Sprintf("\033[%dA", n)
+}
+
+// DownSeq returns the escape sequence to move the cursor down by n lines.
+func DownSeq(n int) string {
+	return fmt.Sprintf("\033[%dB", n)
+}
+
+// LeftSeq returns the escape sequence to move the cursor left by n columns.
+func LeftSeq(n int) string {
+	return fmt.Sprintf("\033[%dD", n)
+}
+
+// RightSeq returns the escape sequence to move the cursor right by n columns.
+func RightSeq(n int) string {
+	return fmt.Sprintf("\033[%dC", n)
+}
+
+// HideCursorSeq returns the escape sequence to hide the cursor.
+func HideCursorSeq() string {
+	return "\033[?25l"
+}
+
+// ShowCursorSeq returns the escape sequence to show the cursor.
+func ShowCursorSeq() string {
+	return "\033[?25h"
+}
 
-// GotoXY returns the escape sequence to move the cursor to the given position.
+// SaveSeq returns the escape sequence to save the cursor position in the single slot of the terminal (DECSC).
+// The slot is also used by the widgets drawn on the screen, see Around, use SaveExact to keep a position meanwhile.
+func SaveSeq() string {
+	return "\0337"
+}
+
+// RestoreSeq returns the escape sequence to move the cursor back to the position saved by SaveSeq (DECRC).
+func RestoreSeq() string {
+	return "\0338"
+}
+
+// SetShapeSeq returns the escape sequence to change the shape of the cursor, such as ShapeBar.
+func SetShapeSeq(s int) string {
+	return fmt.Sprintf("\033[%d q", s)
+}
+
+// GotoXY moves the cursor to row x and column y, counted from 0.
 func GotoXY(x, y int) {
-	fmt.Printf("\033[%d;%dH", x+1, y+1)
+	fmt.Print(GotoXYSeq(x, y))
 }
 
-// Up returns the escape sequence to move the cursor up by n lines.
+// Up moves the cursor up by n lines.
 func Up(n int) {
-	fmt.Printf("\033[%dA", n)
+	fmt.Print(UpSeq(n))
 }
 
-// Down returns the escape sequence to move the cursor down by n lines.
+// Down moves the cursor down by n lines.
 func Down(n int) {
-	fmt.Printf("\033[%dB", n)
+	fmt.Print(DownSeq(n))
 }
 
-// Left returns the escape sequence to move the cursor left by n columns.
+// Left moves the cursor left by n columns.
 func Left(n int) {
-	fmt.Printf("\033[%dD", n)
+	fmt.Print(LeftSeq(n))
 }
 
-// Right returns the escape sequence to move the cursor right by n columns.
+// Right moves the cursor right by n columns.
 func Right(n int) {
-	fmt.Printf("\033[%dC", n)
+	fmt.Print(RightSeq(n))
 }
 
-// HideCursor returns the escape sequence to hide the cursor.
+// HideCursor hides the cursor.
 func HideCursor() {
-	fmt.Printf("\033[?25l")
+	hidden.Store(true)
+	fmt.Print(HideCursorSeq())
 }
 
-// ShowCursor returns the escape sequence to show the cursor.
+// ShowCursor shows the cursor.
 func ShowCursor() {
-	fmt.Printf("\033[?25h")
+	hidden.Store(false)
+	fmt.Print(ShowCursorSeq())
+}
+
+// Hidden reports whether the cursor is hidden by HideCursor.
+func Hidden() bool {
+	return hidden.Load()
+}
+
+// SetShape changes the shape of the cursor, such as ShapeBar. ShapeDefault restores the shape of the terminal.
+func SetShape(s int) {
+	shape.Store(int32(s))
+	fmt.Print(SetShapeSeq(s))
+}
+
+// Shape returns the shape set by SetShape.
+func Shape() int {
+	return int(shape.Load())
+}
+
+// Save pushes the cursor position to a stack, Restore pops it.
+// The position is saved in the single slot of the terminal (DECSC) without asking the terminal,
+// so a nested Save, or a widget drawn on the screen before Restore, overwrites it, see Around.
+// Use SaveExact to nest saves or to draw widgets in between.
+func Save() {
+	fmt.Print(SaveSeq())
+	stackMu.Lock()
+	stack = append(stack, pos{-1, -1})
+	stackMu.Unlock()
+}
+
+// SaveExact pushes the cursor position queried from the terminal to the stack, so that saves can be nested,
+// Restore pops it. It waits up to a second for the reply of the terminal, and falls back to Save if it fails.
+// It must not be called while another goroutine reads stdin, such as a running app.App, see Position.
+func SaveExact() {
+	row, col, err := Position()
+	if err != nil {
+		Save()
+		return
+	}
+	stackMu.Lock()
+	stack = append(stack, pos{row, col})
+	stackMu.Unlock()
+}
+
+// Around returns seq between the escape sequences saving the cursor position in the single slot of the terminal
+// (DECSC) and moving the cursor back to it, for drawing elsewhere on the screen without disturbing
+// the output at the cursor. It never queries the terminal, so it can be called while stdin is read.
+// The slot is overwritten, a position saved by Save cannot be restored after drawing, use SaveExact instead.
+func Around(seq string) string {
+	return SaveSeq() + seq + RestoreSeq()
+}
+
+// Restore pops the position pushed by the last Save and moves the cursor back to it.
+// It does nothing if the stack is empty.
+func Restore() {
+	stackMu.Lock()
+	if len(stack) == 0 {
+		stackMu.Unlock()
+		return
+	}
+	p := stack[len(stack)-1]
+	stack = stack[:len(stack)-1]
+	stackMu.Unlock()
+	if p.row < 0 {
+		fmt.Print(RestoreSeq())
+	} else {
+		GotoXY(p.row, p.col)
+	}
 }
@@ -0,0 +1,112 @@
+package cursor
+
+import (
+	"io"
+	"os"
+	"testing"
+)
+
+// capture returns the output of f on the standard output.
+func capture(t *testing.T, f func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+	out := make(chan string)
+	go func() {
+		b, _ := io.ReadAll(r)
+		out <- string(b)
+	}()
+	f()
+	w.Close()
+	return <-out
+}
+
+func TestSeq(t *testing.T) {
+	tests := []struct {
+		name string
+		seq  string
+		f    func()
+		want string
+	}{
+		{"goto", GotoXYSeq(0, 0), func() { GotoXY(0, 0) }, "\033[1;1H"},
+		{"goto row col", GotoXYSeq(4, 9), func() { GotoXY(4, 9) }, "\033[5;10H"},
+		{"up", UpSeq(2), func() { Up(2) }, "\033[2A"},
+		{"down", DownSeq(3), func() { Down(3) }, "\033[3B"},
+		{"left", LeftSeq(1), func() { Left(1) }, "\033[1D"},
+		{"right", RightSeq(5), func() { Right(5) }, "\033[5C"},
+		{"hide", HideCursorSeq(), HideCursor, "\033[?25l"},
+		{"show", ShowCursorSeq(), ShowCursor, "\033[?25h"},
+		{"shape", SetShapeSeq(ShapeBar), func() { SetShape(ShapeBar) }, "\033[6 q"},
+		{"default shape", SetShapeSeq(ShapeDefault), func() { SetShape(ShapeDefault) }, "\033[0 q"},
+	}
+	for _, tt := range tests {
+		if tt.seq != tt.want {
+			t.Errorf("%s: sequence %q, want %q", tt.name, tt.seq, tt.want)
+		}
+		if out := capture(t, tt.f); out != tt.want {
+			t.Errorf("%s: printed %q, want %q", tt.name, out, tt.want)
+		}
+	}
+}
+
+func TestHiddenAndShape(t *testing.T) {
+	capture(t, HideCursor)
+	if !Hidden() {
+		t.Error("not hidden after HideCursor")
+	}
+	capture(t, ShowCursor)
+	if Hidden() {
+		t.Error("hidden after ShowCursor")
+	}
+	capture(t, func() { SetShape(ShapeBlinkingUnderline) })
+	if Shape() != ShapeBlinkingUnderline {
+		t.Errorf("shape = %d, want %d", Shape(), ShapeBlinkingUnderline)
+	}
+	capture(t, func() { SetShape(ShapeDefault) })
+}
+
+func TestSaveRestore(t *testing.T) {
+	// without a terminal the positions are saved by the terminal
+	if out := capture(t, Save); out != SaveSeq() {
+		t.Errorf("Save printed %q, want %q", out, SaveSeq())
+	}
+	stackMu.Lock()
+	stack = append(stack, pos{3, 7}) // a position queried from the terminal
+	stackMu.Unlock()
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"queried position", GotoXYSeq(3, 7)},
+		{"saved by the terminal", RestoreSeq()},
+		{"empty stack", ""},
+	}
+	for _, tt := range tests {
+		if out := capture(t, Restore); out != tt.want {
+			t.Errorf("%s: Restore printed %q, want %q", tt.name, out, tt.want)
+		}
+	}
+}
+
+func TestPositionWithoutTerminal(t *testing.T) {
+	var err error
+	capture(t, func() { _, _, err = Position() }) // the standard output is a pipe
+	if err == nil {
+		t.Error("no error without a terminal")
+	}
+}
+
+func TestAround(t *testing.T) {
+	// the slot of the terminal is used even if a saved position relies on it, the terminal is never queried
+	capture(t, Save)
+	defer capture(t, Restore)
+	want := SaveSeq() + GotoXYSeq(1, 2) + "x" + RestoreSeq()
+	if got := Around(GotoXYSeq(1, 2) + "x"); got != want {
+		t.Errorf("Around = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,31 @@
+package cursor
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/gngtwhh/gocui/internal/term"
+)
+
+// Position returns the cursor position, counted from 0, by asking the terminal with DSR("\033[6n").
+// It fails if stdin or stdout is not a terminal, or if the terminal does not reply within a second.
+// It must not be called while another goroutine reads stdin, such as a running app.App.
+func Position() (row, col int, err error) {
+	if !term.IsTerminal(int(os.Stdin.Fd())) || !term.IsTerminal(int(os.Stdout.Fd())) {
+		return 0, 0, fmt.Errorf("cursor: not a terminal")
+	}
+	reply, err := term.Query(os.Stdin, os.Stdout, "\033[6n", 'R')
+	if err != nil {
+		return 0, 0, err
+	}
+	// the reply is "\033[<row>;<col>R", input typed before may precede it
+	for i := len(reply) - 1; i >= 0; i-- {
+		if reply[i] == '\033' {
+			if _, err := fmt.Sscanf(string(reply[i:]), "\033[%d;%dR", &row, &col); err != nil {
+				return 0, 0, fmt.Errorf("cursor: unexpected reply %q", reply[i:])
+			}
+			return row - 1, col - 1, nil
+		}
+	}
+	return 0, 0, fmt.Errorf("cursor: unexpected reply %q", reply)
+}
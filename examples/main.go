@@ -7,31 +7,40 @@ import (
 	"os"
 	"time"
 
+	"github.com/gngtwhh/gocui/box"
 	"github.com/gngtwhh/gocui/cursor"
 	"github.com/gngtwhh/gocui/font"
 	"github.com/gngtwhh/gocui/graph"
 	"github.com/gngtwhh/gocui/pb"
+	"github.com/gngtwhh/gocui/widget"
 	"github.com/gngtwhh/gocui/window"
 )
 
 func boxTest() {
-	// payload := []string{
-	// 	"",
-	// 	" 1.Store new books    2.New user registration ",
-	// 	" 3.Borrow books       4.Return books",
-	// 	" 5.All books          6.All user",
-	// 	" 7.Delete database    8.Log out",
-	// 	"",
-	// 	"          Select operation number:",
-	// }
-	// window.ClearScreen()
-
-	// aBox, _ := box.NewBox(box.Property{
-	// 	BoxType: "rounded",
-	// 	Title:   "Books Management System",
-	// 	Payload: payload,
-	// })
-	// box.SetBoxAt(aBox, 0, 0)
+	payload := []string{
+		"",
+		" 1.Store new books    2.New user registration ",
+		" 3.Borrow books       4.Return books",
+		" 5.All books          6.All user",
+		" 7.Delete database    8.Log out",
+		"",
+		"          Select operation number:",
+	}
+	window.ClearScreen()
+
+	outer, _ := box.NewBox(box.WithPos(0, 0), box.WithStyle(box.Style{Color: box.Color{TitleColor: font.Green}}))
+	inner, _ := box.NewBox(box.WithPos(0, 0))
+	bar, _ := pb.NewProgressBar("[%bar] %percent", pb.WithWidth(40))
+	r, _ := bar.Start(100)
+	outer.Panel("Books Management System",
+		widget.Text{Lines: payload},
+		inner.Panel("loading", r),
+	).Print()
+	for range 100 {
+		time.Sleep(time.Millisecond * 30) // Simulate some time-consuming task
+		r.UpdateAdd(1)
+	}
+	r.Stop()
 }
 
 func barTest() {
@@ -153,6 +162,14 @@ func lineTest() {
 	graph.Curve(x, y, length, -1, '*', f)
 }
 
+func plotTest() {
+	p := graph.NewPlot().Domain(-2*math.Pi, 2*math.Pi).
+		Func("sin(x)", font.Green, math.Sin).
+		Func("cos(x)", font.Red, math.Cos)
+	p.Rows, p.Cols = 20, 80
+	p.Print(0, 0)
+}
+
 func windowSizeTest() {
 	w, h := window.GetConsoleSize()
 	fmt.Printf("Command info: weight: %d, height: %d\n", w, h)
@@ -207,17 +224,24 @@ func FontTest() {
 }
 
 func main() {
+	// restore the cursor and colors if interrupted or panicking in the middle of a test
+	defer window.Guard()
+	stop := window.HandleSignals()
+	defer stop()
+
 	//c := '0'
 	runList := []string{
 		"barTest",
 		// "boxTest",
 		// "lineTest",
+		// "plotTest",
 		// "windowSizeTest",
 		// "FontTest",
 	}
 	funcs := map[string]func(){
 		"barTest":        barTest,
 		"lineTest":       lineTest,
+		"plotTest":       plotTest,
 		"boxTest":        boxTest,
 		"windowSizeTest": windowSizeTest,
 		"FontTest":       FontTest,
@@ -0,0 +1,232 @@
+package font
+
+import (
+	"embed"
+	"errors"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// The horizontal layouts of a banner
+const (
+	LayoutDefault  = iota // the layout of the font
+	LayoutFull            // each character keeps its full width
+	LayoutKerning         // characters are moved together until they touch
+	LayoutSmushing        // characters overlap by a column, by the smushing rules of the font
+)
+
+//go:embed fonts/*.flf
+var embedded embed.FS
+
+var (
+	fontsMu sync.Mutex
+	fonts   = make(map[string]*Figlet) // the fonts parsed or registered, by name
+)
+
+// BannerProperty is the property of a banner.
+type BannerProperty struct {
+	Layout   int   // Layout: the horizontal layout, default LayoutDefault
+	MaxWidth int   // MaxWidth: the maximum width, the text is wrapped at spaces to fit, 0 for no limit
+	Colors   []int // Colors: the colors of the rows, cycled through the rows of each line of the text
+	Gradient []int // Gradient: the colors interpolated from the left to the right, overriding Colors
+}
+
+// BannerModFunc is the function to modify the property of a banner.
+type BannerModFunc func(p *BannerProperty)
+
+// WithLayout sets the horizontal layout of the banner.
+func WithLayout(layout int) BannerModFunc {
+	return func(p *BannerProperty) {
+		p.Layout = layout
+	}
+}
+
+// WithMaxWidth wraps the banner to fit width columns.
+func WithMaxWidth(width int) BannerModFunc {
+	return func(p *BannerProperty) {
+		p.MaxWidth = width
+	}
+}
+
+// WithRowColors colors the rows of the banner, cycling through the colors.
+func WithRowColors(colors ...int) BannerModFunc {
+	return func(p *BannerProperty) {
+		p.Colors = colors
+	}
+}
+
+// WithGradient colors the banner with a horizontal gradient through the colors,
+// such as RGB colors or the 16 colors which are converted to their RGB.
+func WithGradient(colors ...int) BannerModFunc {
+	return func(p *BannerProperty) {
+		p.Gradient = colors
+	}
+}
+
+// Fonts returns the names of the fonts, embedded or registered, in order.
+func Fonts() []string {
+	fontsMu.Lock()
+	defer fontsMu.Unlock()
+	names := make([]string, 0, len(fonts))
+	for name := range fonts {
+		names = append(names, name)
+	}
+	entries, _ := embedded.ReadDir("fonts")
+	for _, e := range entries {
+		if name := strings.TrimSuffix(e.Name(), ".flf"); !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+// RegisterFont registers the font with the name for Banner, replacing the font of the same name.
+func RegisterFont(name string, f *Figlet) {
+	fontsMu.Lock()
+	defer fontsMu.Unlock()
+	fonts[name] = f
+}
+
+// GetFont returns the font with the name, parsing the embedded font on the first use.
+func GetFont(name string) (*Figlet, error) {
+	fontsMu.Lock()
+	defer fontsMu.Unlock()
+	if f, ok := fonts[name]; ok {
+		return f, nil
+	}
+	file, err := embedded.Open(path.Join("fonts", name+".flf"))
+	if err != nil {
+		return nil, errors.New("font not found: " + name)
+	}
+	defer file.Close()
+	f, err := ParseFiglet(file)
+	if err != nil {
+		return nil, err
+	}
+	fonts[name] = f
+	return f, nil
+}
+
+// Banner returns the text drawn in large letters with the FIGlet font of the name, such as "block", "banner"
+// or "mini", see Fonts. Each line of the text is drawn as rows of the height of the font.
+// The lines are not padded, they can be placed in a box as widget.Text.
+func Banner(text, fontName string, mfs ...BannerModFunc) ([]string, error) {
+	var p BannerProperty
+	for _, mf := range mfs {
+		if mf == nil {
+			return nil, errors.New("modify func cannot be nil")
+		}
+		mf(&p)
+	}
+	f, err := GetFont(fontName)
+	if err != nil {
+		return nil, err
+	}
+	var rows []string
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		for _, part := range f.wrap(line, p.Layout, p.MaxWidth) {
+			block := f.Render(part, p.Layout)
+			for i := range block {
+				block[i] = strings.TrimRight(block[i], " ")
+				if p.MaxWidth > 0 {
+					block[i] = Truncate(block[i], p.MaxWidth)
+				}
+			}
+			rows = append(rows, p.colorize(block)...)
+		}
+	}
+	return rows, nil
+}
+
+// wrap returns the parts of the line rendered on separate rows to fit width columns,
+// broken at spaces, or between characters for a word wider than width.
+func (f *Figlet) wrap(line string, layout, width int) []string {
+	if width <= 0 {
+		return []string{line}
+	}
+	fits := func(s string) bool { return f.width(s, layout) <= width }
+	var parts []string
+	current := ""
+	for _, word := range strings.Fields(line) {
+		candidate := word
+		if current != "" {
+			candidate = current + " " + word
+		}
+		if fits(candidate) {
+			current = candidate
+			continue
+		}
+		if current != "" {
+			parts = append(parts, current)
+		}
+		current = ""
+		for _, c := range word { // the word alone is too wide, break it between characters
+			if current != "" && !fits(current+string(c)) {
+				parts = append(parts, current)
+				current = ""
+			}
+			current += string(c)
+		}
+	}
+	if current != "" || len(parts) == 0 {
+		parts = append(parts, current)
+	}
+	return parts
+}
+
+// width returns the width of the text rendered in the layout.
+func (f *Figlet) width(text string, layout int) int {
+	w := 0
+	for _, row := range f.Render(text, layout) {
+		w = max(w, Width(strings.TrimRight(row, " ")))
+	}
+	return w
+}
+
+// colorize returns the rows of a line of the text in the colors of the property.
+func (p *BannerProperty) colorize(rows []string) []string {
+	switch {
+	case len(p.Gradient) > 0:
+		width := 0
+		for _, row := range rows {
+			width = max(width, Width(row))
+		}
+		out := make([]string, len(rows))
+		for i, row := range rows {
+			b := strings.Builder{}
+			for col, c := range []rune(row) {
+				if c == ' ' {
+					b.WriteRune(c)
+					continue
+				}
+				b.WriteString(Decorate(string(c), gradientAt(p.Gradient, float64(col)/float64(max(width-1, 1)))))
+			}
+			out[i] = b.String()
+		}
+		return out
+	case len(p.Colors) > 0:
+		out := make([]string, len(rows))
+		for i, row := range rows {
+			out[i] = Decorate(row, p.Colors[i%len(p.Colors)])
+		}
+		return out
+	}
+	return rows
+}
+
+// gradientAt returns the RGB color at t in [0, 1] of the gradient through the colors spaced evenly.
+func gradientAt(colors []int, t float64) int {
+	if len(colors) == 1 {
+		return colors[0]
+	}
+	pos := min(max(t, 0), 1) * float64(len(colors)-1)
+	i := min(int(pos), len(colors)-2)
+	f := pos - float64(i)
+	r1, g1, b1, _ := RGBOf(colors[i])
+	r2, g2, b2, _ := RGBOf(colors[i+1])
+	mix := func(a, b uint8) uint8 { return uint8(float64(a) + (float64(b)-float64(a))*f + 0.5) }
+	return RGB(mix(r1, r2), mix(g1, g2), mix(b1, b2))
+}
@@ -0,0 +1,7 @@
+package font
+
+// Partial blocks in eighths of a cell, the i-th block fills i eighths of the cell from the left or from the bottom.
+var (
+	LeftEighths  = [9]rune{' ', '▏', '▎', '▍', '▌', '▋', '▊', '▉', '█'}
+	LowerEighths = [9]rune{' ', '▁', '▂', '▃', '▄', '▅', '▆', '▇', '█'}
+)
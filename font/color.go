@@ -0,0 +1,169 @@
+package font
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Besides the SGR codes, a color can be a color of the 256-color palette or a true color, encoded in an int
+// by Color256 and RGB, so that it is accepted everywhere an SGR code is, such as Decorate or the colors of widgets.
+// BgColor turns them into background colors as well.
+const (
+	color256Flag = 1 << 24 // the low 8 bits are the index of the 256-color palette
+	colorRGBFlag = 1 << 25 // the low 24 bits are the red, green and blue
+	colorBgFlag  = 1 << 26 // the color is a background color
+)
+
+// The levels of the colors supported by the terminal
+const (
+	Level16 = iota
+	Level256
+	LevelTrueColor
+)
+
+// ColorLevel is the level of the colors supported by the terminal, detected from the environment.
+// Colors above the level are output as the closest color of the level.
+var ColorLevel = detectColorLevel()
+
+// detectColorLevel returns the color level of the terminal by the variables COLORTERM and TERM.
+func detectColorLevel() int {
+	switch colorTerm := os.Getenv("COLORTERM"); {
+	case colorTerm == "truecolor" || colorTerm == "24bit":
+		return LevelTrueColor
+	case strings.Contains(os.Getenv("TERM"), "256color"):
+		return Level256
+	case os.Getenv("WT_SESSION") != "": // Windows Terminal
+		return LevelTrueColor
+	}
+	return Level16
+}
+
+// RGB returns the true color of r, g, b, a foreground color that can be used as an SGR code.
+func RGB(r, g, b uint8) int {
+	return colorRGBFlag | int(r)<<16 | int(g)<<8 | int(b)
+}
+
+// Color256 returns the color n of the 256-color palette, a foreground color that can be used as an SGR code.
+func Color256(n uint8) int {
+	return color256Flag | int(n)
+}
+
+// palette16 are the colors of the 16-color codes 30-37 and 90-97 in the xterm palette
+var palette16 = [16][3]uint8{
+	{0, 0, 0}, {205, 0, 0}, {0, 205, 0}, {205, 205, 0}, {0, 0, 238}, {205, 0, 205}, {0, 205, 205}, {229, 229, 229},
+	{127, 127, 127}, {255, 0, 0}, {0, 255, 0}, {255, 255, 0}, {92, 92, 255}, {255, 0, 255}, {0, 255, 255}, {255, 255, 255},
+}
+
+// cubeLevels are the levels of each component in the 6x6x6 cube of the 256-color palette
+var cubeLevels = [6]uint8{0, 95, 135, 175, 215, 255}
+
+// RGBOf returns the red, green and blue of a foreground or background color of the 16-color codes,
+// of the 256-color palette or a true color. ok is false for other codes, such as RESET or Bold.
+func RGBOf(color int) (r, g, b uint8, ok bool) {
+	color &^= colorBgFlag
+	switch {
+	case color&colorRGBFlag != 0:
+		return uint8(color >> 16), uint8(color >> 8), uint8(color), true
+	case color&color256Flag != 0:
+		n := uint8(color)
+		switch {
+		case n < 16:
+			c := palette16[n]
+			return c[0], c[1], c[2], true
+		case n < 232:
+			n -= 16
+			return cubeLevels[n/36], cubeLevels[n/6%6], cubeLevels[n%6], true
+		}
+		v := 8 + (n-232)*10
+		return v, v, v, true
+	case color >= Black && color <= White:
+		c := palette16[color-Black]
+		return c[0], c[1], c[2], true
+	case color >= LightBlack && color <= LightWhite:
+		c := palette16[color-LightBlack+8]
+		return c[0], c[1], c[2], true
+	case color >= BlackBg && color <= WhiteBg:
+		c := palette16[color-BlackBg]
+		return c[0], c[1], c[2], true
+	case color >= LightBlackBg && color <= LightWhiteBg:
+		c := palette16[color-LightBlackBg+8]
+		return c[0], c[1], c[2], true
+	}
+	return 0, 0, 0, false
+}
+
+// RGBTo256 returns the index of the closest color of r, g, b in the 256-color palette,
+// from the 6x6x6 cube and the grayscale ramp.
+func RGBTo256(r, g, b uint8) uint8 {
+	cube := func(v uint8) int {
+		if v < 48 {
+			return 0
+		}
+		if v < 115 {
+			return 1
+		}
+		return int(v-35) / 40
+	}
+	ci, cj, ck := cube(r), cube(g), cube(b)
+	cubeIndex := uint8(16 + 36*ci + 6*cj + ck)
+	cr, cg, cb := cubeLevels[ci], cubeLevels[cj], cubeLevels[ck]
+
+	avg := (int(r) + int(g) + int(b)) / 3
+	grayIndex := uint8(232 + min(max((avg-3)/10, 0), 23))
+	gv := 8 + (grayIndex-232)*10
+
+	if distance(r, g, b, gv, gv, gv) < distance(r, g, b, cr, cg, cb) {
+		return grayIndex
+	}
+	return cubeIndex
+}
+
+// RGBTo16 returns the foreground code of the closest color of r, g, b in the 16 colors.
+func RGBTo16(r, g, b uint8) int {
+	best, bestDist := 0, -1
+	for i, c := range palette16 {
+		if d := distance(r, g, b, c[0], c[1], c[2]); bestDist < 0 || d < bestDist {
+			best, bestDist = i, d
+		}
+	}
+	if best < 8 {
+		return Black + best
+	}
+	return LightBlack + best - 8
+}
+
+// distance returns the squared distance of two colors, weighted by the sensitivity of the eye.
+func distance(r1, g1, b1, r2, g2, b2 uint8) int {
+	dr, dg, db := int(r1)-int(r2), int(g1)-int(g2), int(b1)-int(b2)
+	return 3*dr*dr + 4*dg*dg + 2*db*db
+}
+
+// sgrCodes returns the parameters of the SGR sequence of a code, downgrading the color to ColorLevel.
+func sgrCodes(code int) string {
+	if code&(color256Flag|colorRGBFlag) == 0 {
+		return strconv.Itoa(code)
+	}
+	bg := code&colorBgFlag != 0
+	r, g, b, _ := RGBOf(code)
+	switch {
+	case code&colorRGBFlag != 0 && ColorLevel >= LevelTrueColor:
+		if bg {
+			return "48;2;" + strconv.Itoa(int(r)) + ";" + strconv.Itoa(int(g)) + ";" + strconv.Itoa(int(b))
+		}
+		return "38;2;" + strconv.Itoa(int(r)) + ";" + strconv.Itoa(int(g)) + ";" + strconv.Itoa(int(b))
+	case ColorLevel >= Level256:
+		n := uint8(code)
+		if code&colorRGBFlag != 0 {
+			n = RGBTo256(r, g, b)
+		}
+		if bg {
+			return "48;5;" + strconv.Itoa(int(n))
+		}
+		return "38;5;" + strconv.Itoa(int(n))
+	}
+	if bg {
+		return strconv.Itoa(RGBTo16(r, g, b) + 10)
+	}
+	return strconv.Itoa(RGBTo16(r, g, b))
+}
@@ -0,0 +1,107 @@
+package font
+
+import "testing"
+
+func TestRGBTo256(t *testing.T) {
+	tests := []struct {
+		r, g, b uint8
+		want    uint8
+	}{
+		{0, 0, 0, 16},
+		{255, 255, 255, 231},
+		{255, 0, 0, 196},
+		{255, 136, 0, 208},
+		{0, 0, 238, 21},
+		{95, 95, 95, 59},
+		{255, 47, 0, 196},
+		{255, 48, 0, 202},
+		{255, 114, 0, 202},
+		{255, 115, 0, 208},
+		{8, 8, 8, 232},
+		{100, 100, 100, 241},
+		{128, 128, 128, 244},
+		{238, 238, 238, 255},
+	}
+	for _, tt := range tests {
+		if got := RGBTo256(tt.r, tt.g, tt.b); got != tt.want {
+			t.Errorf("RGBTo256(%d, %d, %d) = %d, want %d", tt.r, tt.g, tt.b, got, tt.want)
+		}
+	}
+	for n := 16; n < 256; n++ { // the colors of the cube and the grayscale ramp are their own closest color
+		r, g, b, _ := RGBOf(Color256(uint8(n)))
+		if got := RGBTo256(r, g, b); got != uint8(n) {
+			t.Errorf("RGBTo256 of the color %d = %d", n, got)
+		}
+	}
+}
+
+func TestRGBTo16(t *testing.T) {
+	tests := []struct {
+		r, g, b uint8
+		want    int
+	}{
+		{0, 0, 0, Black},
+		{205, 0, 0, Red},
+		{200, 10, 10, Red},
+		{255, 0, 0, LightRed},
+		{0, 0, 238, Blue},
+		{100, 100, 255, LightBlue},
+		{128, 128, 128, LightBlack},
+		{230, 230, 230, White},
+		{250, 250, 250, LightWhite},
+	}
+	for _, tt := range tests {
+		if got := RGBTo16(tt.r, tt.g, tt.b); got != tt.want {
+			t.Errorf("RGBTo16(%d, %d, %d) = %d, want %d", tt.r, tt.g, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestRGBOf(t *testing.T) {
+	tests := []struct {
+		color   int
+		r, g, b uint8
+		ok      bool
+	}{
+		{RGB(1, 2, 3), 1, 2, 3, true},
+		{BgColor(RGB(1, 2, 3)), 1, 2, 3, true},
+		{Color256(1), 205, 0, 0, true},
+		{Color256(208), 255, 135, 0, true},
+		{Color256(232), 8, 8, 8, true},
+		{Color256(255), 238, 238, 238, true},
+		{Red, 205, 0, 0, true},
+		{LightBlueBg, 92, 92, 255, true},
+		{RESET, 0, 0, 0, false},
+		{Bold, 0, 0, 0, false},
+	}
+	for _, tt := range tests {
+		r, g, b, ok := RGBOf(tt.color)
+		if r != tt.r || g != tt.g || b != tt.b || ok != tt.ok {
+			t.Errorf("RGBOf(%#x) = %d, %d, %d, %v, want %d, %d, %d, %v", tt.color, r, g, b, ok, tt.r, tt.g, tt.b, tt.ok)
+		}
+	}
+}
+
+func TestSgrCodes(t *testing.T) {
+	defer func(level int) { ColorLevel = level }(ColorLevel)
+	tests := []struct {
+		code  int
+		level int
+		want  string
+	}{
+		{Red, Level16, "31"},
+		{RGB(255, 136, 0), LevelTrueColor, "38;2;255;136;0"},
+		{BgColor(RGB(255, 136, 0)), LevelTrueColor, "48;2;255;136;0"},
+		{RGB(255, 136, 0), Level256, "38;5;208"},
+		{Color256(208), LevelTrueColor, "38;5;208"},
+		{BgColor(Color256(208)), Level256, "48;5;208"},
+		{RGB(255, 0, 0), Level16, "91"},
+		{BgColor(Color256(1)), Level16, "41"},
+	}
+	for _, tt := range tests {
+		ColorLevel = tt.level
+		if got := sgrCodes(tt.code); got != tt.want {
+			t.Errorf("sgrCodes(%#x) at level %d = %q, want %q", tt.code, tt.level, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,293 @@
+package font
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// The horizontal layout rules of FIGlet fonts, as the bits of the full layout of the header
+const (
+	smushEqual     = 1   // equal characters are smushed into one
+	smushLowline   = 2   // an underscore is replaced by a border character such as | / \ [ ] { } ( ) < >
+	smushHierarchy = 4   // of two border characters of different classes, the latter class wins
+	smushPair      = 8   // opposite brackets are smushed into a vertical bar
+	smushBigX      = 16  // "/\" into "|", "\/" into "Y" and "><" into "X"
+	smushHardblank = 32  // two hardblanks are smushed into one
+	layoutKerning  = 64  // characters are moved together until they touch
+	layoutSmushing = 128 // characters are moved together and overlap by a column, by the rules above
+)
+
+// Figlet is a FIGlet font, parsed from a .flf file by ParseFiglet.
+type Figlet struct {
+	Height    int // Height: the rows of a character
+	Baseline  int // Baseline: the rows from the top to the baseline
+	hardblank rune
+	layout    int // the layout bits of the header
+	chars     map[rune][]string
+}
+
+// ParseFiglet parses a FIGlet font in the format flf2a.
+// The 95 characters of ASCII are required, the 7 German characters and the code-tagged characters are optional.
+func ParseFiglet(r io.Reader) (*Figlet, error) {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 4096), 1<<20)
+	if !sc.Scan() {
+		return nil, errors.New("empty font")
+	}
+	header := sc.Text()
+	if !strings.HasPrefix(header, "flf2a") || len(header) < 6 {
+		return nil, errors.New("not a FIGlet font: bad signature")
+	}
+	hardblank := []rune(header[5:])[0]
+	fields := strings.Fields(header[5+len(string(hardblank)):])
+	if len(fields) < 5 {
+		return nil, errors.New("not a FIGlet font: incomplete header")
+	}
+	nums := make([]int, len(fields))
+	for i, f := range fields {
+		n, err := strconv.Atoi(f)
+		if err != nil {
+			return nil, fmt.Errorf("bad header field %q", f)
+		}
+		nums[i] = n
+	}
+	f := &Figlet{Height: nums[0], Baseline: nums[1], hardblank: hardblank, chars: make(map[rune][]string)}
+	if f.Height <= 0 {
+		return nil, errors.New("bad font height")
+	}
+	switch old := nums[3]; {
+	case len(nums) >= 7:
+		f.layout = nums[6]
+	case old < 0:
+		f.layout = 0
+	case old == 0:
+		f.layout = layoutKerning
+	default:
+		f.layout = old&31 | layoutSmushing
+	}
+	for range nums[4] { // the comment lines
+		sc.Scan()
+	}
+
+	readChar := func() ([]string, bool) {
+		lines := make([]string, 0, f.Height)
+		for range f.Height {
+			if !sc.Scan() {
+				return nil, false
+			}
+			lines = append(lines, trimEndmark(sc.Text()))
+		}
+		return lines, true
+	}
+	codes := make([]rune, 0, 102)
+	for c := rune(32); c < 127; c++ {
+		codes = append(codes, c)
+	}
+	codes = append(codes, 'Ä', 'Ö', 'Ü', 'ä', 'ö', 'ü', 'ß')
+	for i, c := range codes {
+		lines, ok := readChar()
+		if !ok {
+			if i < 95 {
+				return nil, fmt.Errorf("missing the character %q", c)
+			}
+			return f, nil
+		}
+		f.chars[c] = lines
+	}
+	for sc.Scan() { // the code-tagged characters
+		tag := strings.Fields(sc.Text())
+		if len(tag) == 0 {
+			continue
+		}
+		code, err := strconv.ParseInt(tag[0], 0, 32)
+		if err != nil {
+			return nil, fmt.Errorf("bad code tag %q", tag[0])
+		}
+		lines, ok := readChar()
+		if !ok {
+			break
+		}
+		if code >= 0 {
+			f.chars[rune(code)] = lines
+		}
+	}
+	return f, sc.Err()
+}
+
+// trimEndmark removes the endmarks at the end of a line of a character, the endmark is the last character.
+func trimEndmark(line string) string {
+	line = strings.TrimRight(line, "\r\n ")
+	if line == "" {
+		return line
+	}
+	r := []rune(line)
+	mark := r[len(r)-1]
+	for len(r) > 0 && r[len(r)-1] == mark {
+		r = r[:len(r)-1]
+	}
+	return string(r)
+}
+
+// Render returns the text drawn with the font in the layout, one of LayoutFull, LayoutKerning and LayoutSmushing,
+// LayoutDefault for the layout of the font. The characters missing in the font are skipped.
+// The text is drawn on a line, see Banner for wrapping and colors.
+func (f *Figlet) Render(text string, layout int) []string {
+	mode := f.mode(layout)
+	out := make([][]rune, f.Height)
+	prevWidth := 0
+	for _, c := range text {
+		glyph, ok := f.chars[c]
+		if !ok {
+			continue
+		}
+		ch := make([][]rune, f.Height)
+		width := 0
+		for i := range ch {
+			ch[i] = []rune(glyph[i])
+			width = max(width, len(ch[i]))
+		}
+		for i := range ch { // pad the rows of the character to the same width
+			for len(ch[i]) < width {
+				ch[i] = append(ch[i], ' ')
+			}
+		}
+		amount := f.overlap(out, ch, mode, prevWidth, width)
+		for i := range out {
+			for k := 0; k < amount && k < len(ch[i]); k++ {
+				if col := len(out[i]) - amount + k; col >= 0 {
+					out[i][col] = f.smush(out[i][col], ch[i][k], mode, prevWidth, width)
+				}
+			}
+			out[i] = append(out[i], ch[i][min(amount, len(ch[i])):]...)
+		}
+		prevWidth = width
+	}
+	lines := make([]string, f.Height)
+	for i, row := range out {
+		lines[i] = strings.ReplaceAll(string(row), string(f.hardblank), " ")
+	}
+	return lines
+}
+
+// mode returns the layout bits of the layout.
+func (f *Figlet) mode(layout int) int {
+	switch layout {
+	case LayoutFull:
+		return 0
+	case LayoutKerning:
+		return layoutKerning
+	case LayoutSmushing:
+		return f.layout&63 | layoutSmushing // the rules of the font, or the universal smushing if there is none
+	}
+	return f.layout
+}
+
+// overlap returns the columns the character ch overlaps the end of the output when it is added, as in FIGlet:
+// the blanks between them in the row where they are the closest, and a column more if they can be smushed.
+func (f *Figlet) overlap(out, ch [][]rune, mode, prevWidth, width int) int {
+	if mode&(layoutKerning|layoutSmushing) == 0 || len(out[0]) == 0 {
+		return 0
+	}
+	amount := width
+	for i := range out {
+		line := out[i]
+		end := len(line) - 1 // the last character of the row which is not a blank
+		for end > 0 && line[end] == ' ' {
+			end--
+		}
+		start := 0 // the first character of the character which is not a blank
+		for start < len(ch[i]) && ch[i][start] == ' ' {
+			start++
+		}
+		n := start + len(line) - 1 - end
+		var left, right rune = ' ', ' '
+		if end >= 0 {
+			left = line[end]
+		}
+		if start < len(ch[i]) {
+			right = ch[i][start]
+		}
+		if left == ' ' {
+			n++
+		} else if right != ' ' && f.smush(left, right, mode, prevWidth, width) != 0 {
+			n++
+		}
+		amount = min(amount, n)
+	}
+	return amount
+}
+
+// smush returns the character of the left and the right characters smushed, 0 if they cannot be smushed.
+func (f *Figlet) smush(left, right rune, mode, prevWidth, width int) rune {
+	switch {
+	case left == ' ':
+		return right
+	case right == ' ':
+		return left
+	case prevWidth < 2 || width < 2 || mode&layoutSmushing == 0:
+		return 0
+	case mode&63 == 0: // universal smushing, the right character wins except over hardblanks
+		if right == f.hardblank {
+			return left
+		}
+		return right
+	}
+	hb := f.hardblank
+	if mode&smushHardblank != 0 && left == hb && right == hb {
+		return left
+	}
+	if left == hb || right == hb {
+		return 0
+	}
+	if mode&smushEqual != 0 && left == right {
+		return left
+	}
+	if mode&smushLowline != 0 {
+		const borders = "|/\\[]{}()<>"
+		if left == '_' && strings.ContainsRune(borders, right) {
+			return right
+		}
+		if right == '_' && strings.ContainsRune(borders, left) {
+			return left
+		}
+	}
+	if mode&smushHierarchy != 0 {
+		classes := []string{"|", "/\\", "[]", "{}", "()", "<>"}
+		cl, cr := -1, -1
+		for i, class := range classes {
+			if strings.ContainsRune(class, left) {
+				cl = i
+			}
+			if strings.ContainsRune(class, right) {
+				cr = i
+			}
+		}
+		if cl >= 0 && cr >= 0 && cl != cr {
+			if cl > cr {
+				return left
+			}
+			return right
+		}
+	}
+	if mode&smushPair != 0 {
+		switch string([]rune{left, right}) {
+		case "[]", "][", "{}", "}{", "()", ")(":
+			return '|'
+		}
+	}
+	if mode&smushBigX != 0 {
+		switch string([]rune{left, right}) {
+		case "/\\":
+			return '|'
+		case "\\/":
+			return 'Y'
+		case "><":
+			return 'X'
+		}
+	}
+	return 0
+}
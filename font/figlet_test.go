@@ -0,0 +1,208 @@
+package font
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+// testFont returns a font in the format flf2a with the header, two rows high,
+// made of the glyphs and blanks for the other ASCII characters, followed by extra.
+func testFont(header string, glyphs map[rune][2]string, extra string) string {
+	b := strings.Builder{}
+	b.WriteString(header + "\na comment\n")
+	for c := rune(32); c < 127; c++ {
+		g, ok := glyphs[c]
+		if !ok {
+			g = [2]string{"$", "$"}
+		}
+		b.WriteString(g[0] + "@\n" + g[1] + "@@\n")
+	}
+	b.WriteString(extra)
+	return b.String()
+}
+
+var testGlyphs = map[rune][2]string{
+	'o': {"   ", " o "},
+	'T': {"TTT", " T "},
+}
+
+func TestParseFiglet(t *testing.T) {
+	german := strings.Repeat("ä@\nä@@\n", 7)
+	tests := []struct {
+		name    string
+		font    string
+		wantErr bool
+		layout  int
+		chars   map[rune][]string
+	}{
+		{"empty", "", true, 0, nil},
+		{"bad signature", "flf2b$ 2 1 4 0 1", true, 0, nil},
+		{"incomplete header", "flf2a$ 2 1 4", true, 0, nil},
+		{"bad header field", "flf2a$ 2 1 x 0 1", true, 0, nil},
+		{"bad height", testFont("flf2a$ 0 1 4 0 1", nil, ""), true, 0, nil},
+		{"missing characters", "flf2a$ 2 1 4 0 0\n$@\n$@@\n", true, 0, nil},
+		{"full width", testFont("flf2a$ 2 1 4 -1 1", testGlyphs, ""), false, 0, map[rune][]string{'o': {"   ", " o "}}},
+		{"kerning", testFont("flf2a$ 2 1 4 0 1", testGlyphs, ""), false, layoutKerning, nil},
+		{"old smushing", testFont("flf2a$ 2 1 4 15 1", testGlyphs, ""), false, 15 | layoutSmushing, nil},
+		{"full layout", testFont("flf2a$ 2 1 4 15 1 0 64", testGlyphs, ""), false, layoutKerning, nil},
+		{"german characters", testFont("flf2a$ 2 1 4 0 1", nil, german), false, layoutKerning,
+			map[rune][]string{'ß': {"ä", "ä"}}},
+		{"code-tagged characters", testFont("flf2a$ 2 1 4 0 1", nil, german+"0x263A  smiley\n:)@\n(:@@\n"), false,
+			layoutKerning, map[rune][]string{'☺': {":)", "(:"}}},
+		{"bad code tag", testFont("flf2a$ 2 1 4 0 1", nil, german+"smiley\n:)@\n(:@@\n"), true, 0, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := ParseFiglet(strings.NewReader(tt.font))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("no error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if f.Height != 2 || f.Baseline != 1 || f.hardblank != '$' {
+				t.Errorf("header = %d %d %q, want 2 1 '$'", f.Height, f.Baseline, f.hardblank)
+			}
+			if f.layout != tt.layout {
+				t.Errorf("layout = %d, want %d", f.layout, tt.layout)
+			}
+			for c, want := range tt.chars {
+				if got := f.chars[c]; !slices.Equal(got, want) {
+					t.Errorf("char %q = %q, want %q", c, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestTrimEndmark(t *testing.T) {
+	tests := []struct{ line, want string }{
+		{"", ""},
+		{"ab@", "ab"},
+		{"ab@@", "ab"},
+		{" _ #", " _ "},
+		{"ab@@ \r\n", "ab"},
+	}
+	for _, tt := range tests {
+		if got := trimEndmark(tt.line); got != tt.want {
+			t.Errorf("trimEndmark(%q) = %q, want %q", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestSmush(t *testing.T) {
+	const all = smushEqual | smushLowline | smushHierarchy | smushPair | smushBigX | smushHardblank | layoutSmushing
+	f := &Figlet{hardblank: '$'}
+	tests := []struct {
+		left, right rune
+		mode        int
+		prevWidth   int
+		want        rune
+	}{
+		{' ', 'a', 0, 2, 'a'},
+		{'a', ' ', 0, 2, 'a'},
+		{'a', 'b', layoutKerning, 2, 0},
+		{'a', 'b', layoutSmushing, 1, 0},
+		{'a', 'b', layoutSmushing, 2, 'b'},
+		{'a', '$', layoutSmushing, 2, 'a'},
+		{'|', '|', smushEqual | layoutSmushing, 2, '|'},
+		{'a', 'b', smushEqual | layoutSmushing, 2, 0},
+		{'_', '/', smushLowline | layoutSmushing, 2, '/'},
+		{'(', '_', smushLowline | layoutSmushing, 2, '('},
+		{'_', 'a', smushLowline | layoutSmushing, 2, 0},
+		{'|', '/', smushHierarchy | layoutSmushing, 2, '/'},
+		{'>', '[', smushHierarchy | layoutSmushing, 2, '>'},
+		{'[', ']', smushPair | layoutSmushing, 2, '|'},
+		{')', '(', smushPair | layoutSmushing, 2, '|'},
+		{'/', '\\', smushBigX | layoutSmushing, 2, '|'},
+		{'\\', '/', smushBigX | layoutSmushing, 2, 'Y'},
+		{'>', '<', smushBigX | layoutSmushing, 2, 'X'},
+		{'$', '$', smushHardblank | layoutSmushing, 2, '$'},
+		{'$', '$', smushEqual | layoutSmushing, 2, 0},
+		{'$', '|', all, 2, 0},
+	}
+	for _, tt := range tests {
+		if got := f.smush(tt.left, tt.right, tt.mode, tt.prevWidth, 2); got != tt.want {
+			t.Errorf("smush(%q, %q, %d, %d) = %q, want %q", tt.left, tt.right, tt.mode, tt.prevWidth, got, tt.want)
+		}
+	}
+}
+
+func TestRender(t *testing.T) {
+	f, err := ParseFiglet(strings.NewReader(testFont("flf2a$ 2 1 4 1 1", testGlyphs, "")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		text   string
+		layout int
+		want   []string
+	}{
+		{"oo", LayoutFull, []string{"      ", " o  o "}},
+		{"oo", LayoutKerning, []string{"    ", " oo "}},
+		{"oo", LayoutSmushing, []string{"   ", " o "}},
+		{"TT", LayoutDefault, []string{"TTTTT", " T T "}},
+		{"TT", LayoutKerning, []string{"TTTTTT", " T  T "}},
+		{"o o", LayoutFull, []string{"       ", " o   o "}},
+		{"o\x00o", LayoutFull, []string{"      ", " o  o "}},
+	}
+	for _, tt := range tests {
+		if got := f.Render(tt.text, tt.layout); !slices.Equal(got, tt.want) {
+			t.Errorf("Render(%q, %d) = %q, want %q", tt.text, tt.layout, got, tt.want)
+		}
+	}
+}
+
+func TestBanner(t *testing.T) {
+	f, err := ParseFiglet(strings.NewReader(testFont("flf2a$ 2 1 4 1 1", testGlyphs, "")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	RegisterFont("test", f)
+	tests := []struct {
+		name string
+		text string
+		mfs  []BannerModFunc
+		want []string
+	}{
+		{"default", "TT", nil, []string{"TTTTT", " T T"}},
+		{"lines", "T\nT", nil, []string{"TTT", " T", "TTT", " T"}},
+		{"wrapped", "T T", []BannerModFunc{WithLayout(LayoutFull), WithMaxWidth(4)}, []string{"TTT", " T", "TTT", " T"}},
+		{"long word", "TTT", []BannerModFunc{WithMaxWidth(6)}, []string{"TTTTT", " T T", "TTT", " T"}},
+		{"row colors", "T", []BannerModFunc{WithRowColors(Red)}, []string{Decorate("TTT", Red), Decorate(" T", Red)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Banner(tt.text, "test", tt.mfs...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Banner(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+	if _, err := Banner("T", "missing"); err == nil {
+		t.Error("no error for a missing font")
+	}
+	if _, err := Banner("T", "test", nil); err == nil {
+		t.Error("no error for a nil modify func")
+	}
+}
+
+func TestEmbeddedFonts(t *testing.T) {
+	for _, name := range []string{"banner", "block", "mini"} {
+		f, err := GetFont(name)
+		if err != nil {
+			t.Errorf("font %s: %v", name, err)
+			continue
+		}
+		if rows := f.Render("Hi!", LayoutDefault); len(rows) != f.Height || Width(rows[0]) == 0 {
+			t.Errorf("font %s renders %q", name, rows)
+		}
+	}
+}
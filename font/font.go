@@ -59,8 +59,20 @@ const (
 	LightWhiteBg   = 107
 )
 
+// BgColor returns the background color of the foreground color, including the colors of Color256 and RGB,
+// other codes are returned as they are.
+func BgColor(color int) int {
+	if (color >= Black && color <= White) || (color >= LightBlack && color <= LightWhite) {
+		return color + 10
+	}
+	if color&(color256Flag|colorRGBFlag) != 0 {
+		return color | colorBgFlag
+	}
+	return color
+}
+
 func SetColor(color int) {
-	fmt.Printf("\033[%dm", color)
+	fmt.Printf("\033[%sm", sgrCodes(color))
 }
 
 func SetColorRgb(r, g, b int, bg bool) {
@@ -76,7 +88,7 @@ func ResetColor() {
 }
 
 func SetStyle(style int) string {
-	return fmt.Sprintf("\033[%dm", style)
+	return fmt.Sprintf("\033[%sm", sgrCodes(style))
 }
 
 func Decorate(text string, style ...int) string {
@@ -84,7 +96,7 @@ func Decorate(text string, style ...int) string {
 	buf.WriteString("\033[")
 	l := len(style)
 	for i, s := range style {
-		buf.WriteString(fmt.Sprintf("%d", s))
+		buf.WriteString(sgrCodes(s))
 		if i < l-1 {
 			buf.WriteString(";")
 		}
@@ -100,7 +112,7 @@ func Splice(ks ...interface{}) string {
 	for _, k := range ks {
 		switch v := k.(type) {
 		case int:
-			buf.WriteString(fmt.Sprintf("\033[%dm", v))
+			buf.WriteString(fmt.Sprintf("\033[%sm", sgrCodes(v)))
 		case rune:
 			buf.WriteRune(v)
 		case string:
@@ -0,0 +1,264 @@
+package font
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Markup returns the text with its markup tags turned into SGR sequences, formatted with args like fmt.Sprintf
+// if there are any. The args are not parsed as markup.
+//
+// A tag "[style]" applies the style until the matching "[/style]", or "[/]" closing the last open tag,
+// and tags nest: closing a tag restores the styles of the outer tags. Open tags are closed at the end.
+// A style is a list of words separated by spaces:
+//   - the attributes bold (b), dim, italic (i), underline (u), blink, reverse, hidden and strike (s)
+//   - a foreground color: a name such as red or bright_red, a hex color "#f80" or "#ff8800",
+//     "rgb(255,136,0)", or a color of the 256-color palette "color(208)"
+//   - a background color: "on" followed by a color
+//
+// "\[" is a literal bracket, and so is a bracket whose content is not a valid style, such as "[1/2]".
+func Markup(text string, args ...any) string {
+	return markup(text, true, args...)
+}
+
+// MarkupPlain returns the text of Markup without the styles.
+func MarkupPlain(text string, args ...any) string {
+	return markup(text, false, args...)
+}
+
+// EscapeMarkup escapes the brackets of the text, so that it is shown as is by Markup.
+func EscapeMarkup(text string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(text, `\`, `\\`), "[", `\[`)
+}
+
+// markupStyle is the style of an open tag.
+type markupStyle struct {
+	tag    string // the content of the tag, to match its closing tag
+	attrs  []int
+	fg, bg int
+}
+
+// markup parses the text and writes the styles if styled, with the args formatted into the text runs.
+func markup(text string, styled bool, args ...any) string {
+	var stack []markupStyle
+	b := strings.Builder{}
+	run := strings.Builder{} // the text since the last tag, formatted with the args
+	argi := 0
+	flush := func() {
+		s := run.String()
+		run.Reset()
+		if len(args) > 0 {
+			var n int
+			s, n = formatRun(s, args[argi:])
+			argi += n
+		}
+		if styled && len(stack) > 0 {
+			// a reset in the args, such as the end of Decorate, restores the styles of the open tags
+			s = strings.ReplaceAll(s, "\033[0m", "\033[0m"+sgrOf(stack))
+		}
+		b.WriteString(s)
+	}
+	apply := func() {
+		if styled {
+			b.WriteString("\033[0m" + sgrOf(stack))
+		}
+	}
+
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		if c == '\\' && i+1 < len(text) && (text[i+1] == '[' || text[i+1] == '\\') {
+			run.WriteByte(text[i+1])
+			i++
+			continue
+		}
+		if c != '[' {
+			run.WriteByte(c)
+			continue
+		}
+		end := strings.IndexByte(text[i:], ']')
+		if end < 0 {
+			run.WriteByte(c)
+			continue
+		}
+		tag := text[i+1 : i+end]
+		if name, ok := strings.CutPrefix(tag, "/"); ok {
+			k := len(stack) - 1 // the tag closed, the last one for "[/]"
+			for name != "" && k >= 0 && stack[k].tag != strings.TrimSpace(name) {
+				k--
+			}
+			if k < 0 {
+				run.WriteByte(c) // not an open tag, kept as text
+				continue
+			}
+			flush()
+			stack = append(stack[:k], stack[k+1:]...)
+			apply()
+			i += end
+			continue
+		}
+		style, ok := parseStyle(tag)
+		if !ok {
+			run.WriteByte(c)
+			continue
+		}
+		flush()
+		stack = append(stack, style)
+		if styled {
+			b.WriteString(sgrOf(stack[len(stack)-1:]))
+		}
+		i += end
+	}
+	flush()
+	if styled && len(stack) > 0 {
+		b.WriteString("\033[0m")
+	}
+	return b.String()
+}
+
+// formatRun formats a run of text by fmt.Sprintf with the first args it needs, returning the number of args used.
+func formatRun(s string, args []any) (string, int) {
+	n := 0
+	for i := 0; i < len(s); i++ {
+		if s[i] != '%' {
+			continue
+		}
+		if i+1 < len(s) && s[i+1] == '%' {
+			i++
+			continue
+		}
+		n++
+	}
+	n = min(n, len(args))
+	return fmt.Sprintf(s, args[:n]...), n
+}
+
+// sgrOf returns the SGR sequence of the styles, the later styles override the colors of the former.
+func sgrOf(stack []markupStyle) string {
+	var codes []int
+	fg, bg := RESET, RESET
+	for _, s := range stack {
+		codes = append(codes, s.attrs...)
+		if s.fg != RESET {
+			fg = s.fg
+		}
+		if s.bg != RESET {
+			bg = s.bg
+		}
+	}
+	if fg != RESET {
+		codes = append(codes, fg)
+	}
+	if bg != RESET {
+		codes = append(codes, bg)
+	}
+	if len(codes) == 0 {
+		return ""
+	}
+	params := make([]string, len(codes))
+	for i, c := range codes {
+		params[i] = sgrCodes(c)
+	}
+	return "\033[" + strings.Join(params, ";") + "m"
+}
+
+// markupAttrs are the attributes of markup by name
+var markupAttrs = map[string]int{
+	"bold": Bold, "b": Bold, "dim": Dim, "italic": Italic, "i": Italic, "underline": Underline, "u": Underline,
+	"blink": BlinkSlow, "reverse": Reverse, "hidden": Hide, "strike": CrossedOut, "s": CrossedOut,
+}
+
+// markupColors are the 16 colors of markup by name, the bright colors are also named light and bright_
+var markupColors = map[string]int{
+	"black": Black, "red": Red, "green": Green, "yellow": Yellow,
+	"blue": Blue, "magenta": Magenta, "cyan": Cyan, "white": White, "gray": LightBlack, "grey": LightBlack,
+}
+
+// parseStyle parses the content of a tag, ok is false if it is not a style.
+func parseStyle(tag string) (s markupStyle, ok bool) {
+	words := strings.Fields(tag)
+	if len(words) == 0 {
+		return s, false
+	}
+	s.tag = strings.Join(words, " ")
+	for i := 0; i < len(words); i++ {
+		w := strings.ToLower(words[i])
+		if attr, ok := markupAttrs[w]; ok {
+			s.attrs = append(s.attrs, attr)
+			continue
+		}
+		if w == "on" && i+1 < len(words) {
+			c, ok := ParseColor(words[i+1])
+			if !ok {
+				return s, false
+			}
+			s.bg = BgColor(c)
+			i++
+			continue
+		}
+		c, ok := ParseColor(w)
+		if !ok {
+			return s, false
+		}
+		s.fg = c
+	}
+	return s, true
+}
+
+// ParseColor parses a foreground color: a name of the 16 colors such as "red", "bright_red" or "lightred",
+// a hex color "#f80" or "#ff8800", "rgb(255,136,0)", or "color(208)" of the 256-color palette.
+func ParseColor(s string) (int, bool) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if c, ok := markupColors[s]; ok {
+		return c, true
+	}
+	for _, prefix := range []string{"bright_", "bright", "light_", "light"} {
+		if name, ok := strings.CutPrefix(s, prefix); ok {
+			if c, ok := markupColors[name]; ok && c >= Black && c <= White {
+				return c + LightBlack - Black, true
+			}
+		}
+	}
+	if hex, ok := strings.CutPrefix(s, "#"); ok {
+		if len(hex) == 3 {
+			hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+		}
+		n, err := strconv.ParseUint(hex, 16, 32)
+		if len(hex) != 6 || err != nil {
+			return 0, false
+		}
+		return RGB(uint8(n>>16), uint8(n>>8), uint8(n)), true
+	}
+	if args, ok := cutCall(s, "rgb"); ok {
+		parts := strings.Split(args, ",")
+		if len(parts) != 3 {
+			return 0, false
+		}
+		var rgb [3]uint8
+		for i, p := range parts {
+			n, err := strconv.ParseUint(strings.TrimSpace(p), 10, 8)
+			if err != nil {
+				return 0, false
+			}
+			rgb[i] = uint8(n)
+		}
+		return RGB(rgb[0], rgb[1], rgb[2]), true
+	}
+	if args, ok := cutCall(s, "color"); ok {
+		n, err := strconv.ParseUint(strings.TrimSpace(args), 10, 8)
+		if err != nil {
+			return 0, false
+		}
+		return Color256(uint8(n)), true
+	}
+	return 0, false
+}
+
+// cutCall returns the arguments of s in the form name(args).
+func cutCall(s, name string) (string, bool) {
+	rest, ok := strings.CutPrefix(s, name+"(")
+	if !ok || !strings.HasSuffix(rest, ")") {
+		return "", false
+	}
+	return strings.TrimSuffix(rest, ")"), true
+}
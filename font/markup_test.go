@@ -0,0 +1,79 @@
+package font
+
+import "testing"
+
+func TestParseColor(t *testing.T) {
+	tests := []struct {
+		s      string
+		want   int
+		wantOk bool
+	}{
+		{"red", Red, true},
+		{" Red ", Red, true},
+		{"grey", LightBlack, true},
+		{"bright_red", LightRed, true},
+		{"brightred", LightRed, true},
+		{"light_blue", LightBlue, true},
+		{"lightwhite", LightWhite, true},
+		{"bright_gray", 0, false},
+		{"#f80", RGB(0xff, 0x88, 0), true},
+		{"#FF8800", RGB(0xff, 0x88, 0), true},
+		{"#ff88", 0, false},
+		{"#ggg", 0, false},
+		{"rgb(255,136,0)", RGB(255, 136, 0), true},
+		{"rgb( 1, 2, 3 )", RGB(1, 2, 3), true},
+		{"rgb(1,2)", 0, false},
+		{"rgb(256,0,0)", 0, false},
+		{"rgb(1,2,3", 0, false},
+		{"color(208)", Color256(208), true},
+		{"color(256)", 0, false},
+		{"", 0, false},
+		{"purple", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := ParseColor(tt.s)
+		if got != tt.want || ok != tt.wantOk {
+			t.Errorf("ParseColor(%q) = %d, %v, want %d, %v", tt.s, got, ok, tt.want, tt.wantOk)
+		}
+	}
+}
+
+func TestMarkup(t *testing.T) {
+	defer func(level int) { ColorLevel = level }(ColorLevel)
+	ColorLevel = LevelTrueColor
+	tests := []struct {
+		text  string
+		args  []any
+		want  string
+		plain string
+	}{
+		{"plain", nil, "plain", "plain"},
+		{"[bold]b[/bold]", nil, "\033[1mb\033[0m", "b"},
+		{"[b red]x[/] y", nil, "\033[1;31mx\033[0m y", "x y"},
+		{"[red]r[on blue]rb[/on blue]r[/red]", nil,
+			"\033[31mr\033[44mrb\033[0m\033[31mr\033[0m", "rrbr"},
+		{"[red]a[blue]b[/red]c[/]", nil, "\033[31ma\033[34mb\033[0m\033[34mc\033[0m", "abc"},
+		{"[#ff8800]o", nil, "\033[38;2;255;136;0mo\033[0m", "o"},
+		{"[on color(208)]o[/]", nil, "\033[48;5;208mo\033[0m", "o"},
+		{`\[red] \\`, nil, `[red] \`, `[red] \`},
+		{"[1/2] [/x] [", nil, "[1/2] [/x] [", "[1/2] [/x] ["},
+		{"[red]%d%%[/] %s", []any{5, "[b]"}, "\033[31m5%\033[0m [b]", "5% [b]"},
+		{"[u]%s!", []any{Decorate("d", Red)}, "\033[4m\033[31md\033[0m\033[4m!\033[0m", "\033[31md\033[0m!"},
+	}
+	for _, tt := range tests {
+		if got := Markup(tt.text, tt.args...); got != tt.want {
+			t.Errorf("Markup(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+		if got := MarkupPlain(tt.text, tt.args...); got != tt.plain {
+			t.Errorf("MarkupPlain(%q) = %q, want %q", tt.text, got, tt.plain)
+		}
+	}
+}
+
+func TestEscapeMarkup(t *testing.T) {
+	for _, s := range []string{"[red]x[/]", `a\[b\`, `\\[`, "plain"} {
+		if got := MarkupPlain(EscapeMarkup(s)); got != s {
+			t.Errorf("MarkupPlain(EscapeMarkup(%q)) = %q", s, got)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package font
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Width returns the number of terminal columns the text occupies.
+// Escape sequences are ignored, East Asian wide characters and emojis count as two columns.
+func Width(text string) int {
+	w, prev := 0, 0 // prev: the width of the last character
+	for i := 0; i < len(text); {
+		if n := EscapeLen(text[i:]); n > 0 {
+			i += n
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(text[i:])
+		rw := RuneWidth(r)
+		if r == EmojiPresentation && prev == 1 {
+			rw = 1 // the previous character is shown as a wide emoji
+			prev = 2
+		} else {
+			prev = rw
+		}
+		w += rw
+		i += size
+	}
+	return w
+}
+
+// Strip removes all the escape sequences from the text.
+func Strip(text string) string {
+	buf := strings.Builder{}
+	for i := 0; i < len(text); {
+		if n := EscapeLen(text[i:]); n > 0 {
+			i += n
+			continue
+		}
+		_, size := utf8.DecodeRuneInString(text[i:])
+		buf.WriteString(text[i : i+size])
+		i += size
+	}
+	return buf.String()
+}
+
+// Truncate cuts the text to at most width columns, keeping the escape sequences in front of the cut.
+// If the text was cut while styled, a reset sequence is appended so the style does not leak.
+func Truncate(text string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	buf := strings.Builder{}
+	w, prev, styled := 0, 0, false
+	for i := 0; i < len(text); {
+		if n := EscapeLen(text[i:]); n > 0 {
+			buf.WriteString(text[i : i+n])
+			styled = true
+			i += n
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(text[i:])
+		rw := RuneWidth(r)
+		if r == EmojiPresentation && prev == 1 {
+			rw = 1 // the previous character is shown as a wide emoji
+			prev = 2
+		} else {
+			prev = rw
+		}
+		if w+rw > width {
+			if styled {
+				buf.WriteString("\033[0m")
+			}
+			return buf.String()
+		}
+		w += rw
+		buf.WriteString(text[i : i+size])
+		i += size
+	}
+	return buf.String()
+}
+
+// Pad truncates or right pads the text with spaces to exactly width columns.
+func Pad(text string, width int) string {
+	text = Truncate(text, width)
+	if w := Width(text); w < width {
+		text += strings.Repeat(" ", width-w)
+	}
+	return text
+}
+
+// Center truncates the text to width columns and centers it, padded with spaces to exactly width columns.
+// When the space left is odd, the extra space goes to the right.
+func Center(text string, width int) string {
+	text = Truncate(text, width)
+	return Pad(strings.Repeat(" ", (width-Width(text))/2)+text, width)
+}
+
+// EmojiPresentation is the variation selector 16, which shows the character before it as a wide emoji,
+// such as "❤️", so that character occupies two columns in the text.
+const EmojiPresentation = '\uFE0F'
+
+// RuneWidth returns the number of terminal columns the rune occupies.
+// The rune is measured alone, see EmojiPresentation for the width it adds in a text.
+func RuneWidth(r rune) int {
+	switch {
+	case r == 0 || r < 32 || (r >= 0x7f && r < 0xa0):
+		return 0
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf):
+		return 0
+	case isWide(r):
+		return 2
+	}
+	return 1
+}
+
+// isWide reports whether the rune is an East Asian wide or fullwidth character.
+func isWide(r rune) bool {
+	return r >= 0x1100 && (r <= 0x115f || // Hangul Jamo
+		r == 0x2329 || r == 0x232a ||
+		(r >= 0x2e80 && r <= 0xa4cf && r != 0x303f) || // CJK ... Yi
+		(r >= 0xac00 && r <= 0xd7a3) || // Hangul Syllables
+		(r >= 0xf900 && r <= 0xfaff) || // CJK Compatibility Ideographs
+		(r >= 0xfe10 && r <= 0xfe19) || // Vertical forms
+		(r >= 0xfe30 && r <= 0xfe6f) || // CJK Compatibility Forms
+		(r >= 0xff00 && r <= 0xff60) || // Fullwidth Forms
+		(r >= 0xffe0 && r <= 0xffe6) ||
+		(r >= 0x1f300 && r <= 0x1f64f) || // Pictographs and emoticons
+		(r >= 0x1f900 && r <= 0x1f9ff) ||
+		(r >= 0x20000 && r <= 0x3fffd))
+}
+
+// EscapeLen returns the length of the escape sequence at the beginning of s, or 0 if there is none.
+func EscapeLen(s string) int {
+	if len(s) < 2 || s[0] != '\033' {
+		return 0
+	}
+	switch s[1] {
+	case '[': // CSI: parameters end with a byte in 0x40-0x7e
+		for i := 2; i < len(s); i++ {
+			if s[i] >= 0x40 && s[i] <= 0x7e {
+				return i + 1
+			}
+		}
+		return len(s)
+	case ']', 'P', '_': // OSC, DCS and APC end with BEL or ST
+		for i := 2; i < len(s); i++ {
+			if s[i] == '\a' {
+				return i + 1
+			}
+			if s[i] == '\033' && i+1 < len(s) && s[i+1] == '\\' {
+				return i + 2
+			}
+		}
+		return len(s)
+	}
+	return 2
+}
@@ -0,0 +1,78 @@
+package font
+
+import "testing"
+
+func TestWidth(t *testing.T) {
+	tests := []struct {
+		text  string
+		width int
+	}{
+		{"", 0},
+		{"abc", 3},
+		{"北京", 4},
+		{"a\033[31mb\033[0m", 2},
+		{"\033]8;;http://x\a link\033]8;;\a", 5},
+		{"é", 1},
+		{"🌍", 2},
+		{"\t", 0},
+		{"❤️", 2},
+		{"a️", 2},
+		{"北️", 2},
+	}
+	for _, tt := range tests {
+		if got := Width(tt.text); got != tt.width {
+			t.Errorf("Width(%q) = %d, want %d", tt.text, got, tt.width)
+		}
+	}
+}
+
+func TestTruncatePad(t *testing.T) {
+	tests := []struct {
+		text  string
+		width int
+		trunc string
+		pad   string
+	}{
+		{"abc", 5, "abc", "abc  "},
+		{"abcdef", 3, "abc", "abc"},
+		{"北京", 3, "北", "北 "},
+		{"\033[31mabc\033[0m", 2, "\033[31mab\033[0m", "\033[31mab\033[0m"},
+		{"\033[31mab\033[0m", 3, "\033[31mab\033[0m", "\033[31mab\033[0m "},
+		{"abc", 0, "", ""},
+		{"❤️a", 2, "❤️", "❤️"},
+		{"x❤️", 2, "x❤", "x❤"}, // the heart without the selector is narrow
+	}
+	for _, tt := range tests {
+		if got := Truncate(tt.text, tt.width); got != tt.trunc {
+			t.Errorf("Truncate(%q, %d) = %q, want %q", tt.text, tt.width, got, tt.trunc)
+		}
+		if got := Pad(tt.text, tt.width); got != tt.pad {
+			t.Errorf("Pad(%q, %d) = %q, want %q", tt.text, tt.width, got, tt.pad)
+		}
+	}
+	if got := Strip("\033[1ma\033[0mb"); got != "ab" {
+		t.Errorf("Strip = %q, want %q", got, "ab")
+	}
+}
+
+func TestCenter(t *testing.T) {
+	tests := []struct {
+		text  string
+		width int
+		want  string
+	}{
+		{"ab", 6, "  ab  "},
+		{"ab", 5, " ab  "},
+		{"北京", 6, " 北京 "},
+		{"北京", 3, "北 "},
+		{"abcdef", 4, "abcd"},
+		{"\033[31mab\033[0m", 4, " \033[31mab\033[0m "},
+		{"", 2, "  "},
+		{"ab", 0, ""},
+	}
+	for _, tt := range tests {
+		if got := Center(tt.text, tt.width); got != tt.want {
+			t.Errorf("Center(%q, %d) = %q, want %q", tt.text, tt.width, got, tt.want)
+		}
+	}
+}
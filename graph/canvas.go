@@ -0,0 +1,246 @@
+package graph
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/gngtwhh/gocui/font"
+	"github.com/gngtwhh/gocui/widget"
+)
+
+// Canvas modes, the number of pixels in a cell
+const (
+	Braille   = iota // 2 columns x 4 rows of dots per cell
+	HalfBlock        // 1 column x 2 rows of half blocks per cell
+	Cell             // 1 pixel per cell
+)
+
+// Stroke is how the pixels are drawn.
+type Stroke struct {
+	Char  rune // Char: the character of a pixel in the Cell mode, default '█'
+	Color int  // Color: the color of the pixels, default font.RESET
+}
+
+// Canvas is a surface of pixels smaller than a cell.
+// The coordinates of the pixels are x for the row and y for the column, as in the rest of the package,
+// pixels outside the canvas are clipped.
+// A cell has the color of the last pixel set in it, except in the HalfBlock mode,
+// where the upper and the lower pixel keep their own colors.
+type Canvas struct {
+	mode       int
+	rows, cols int // size in cells
+	pw, ph     int // pixels per cell, in columns and rows
+	stroke     Stroke
+
+	pixels []bool
+	colors []int  // color of each pixel in the HalfBlock mode, of each cell in other modes
+	chars  []rune // character of each cell in the Cell mode
+}
+
+// NewCanvas creates a blank canvas of rows x cols cells in the mode: Braille, HalfBlock or Cell.
+func NewCanvas(rows, cols, mode int) *Canvas {
+	c := &Canvas{mode: mode, rows: max(rows, 0), cols: max(cols, 0)}
+	switch mode {
+	case Braille:
+		c.pw, c.ph = 2, 4
+	case HalfBlock:
+		c.pw, c.ph = 1, 2
+	default:
+		c.mode, c.pw, c.ph = Cell, 1, 1
+	}
+	c.pixels = make([]bool, c.rows*c.ph*c.cols*c.pw)
+	if c.mode == HalfBlock {
+		c.colors = make([]int, len(c.pixels))
+	} else {
+		c.colors = make([]int, c.rows*c.cols)
+	}
+	c.chars = make([]rune, c.rows*c.cols)
+	return c
+}
+
+// Mode returns the mode of the canvas.
+func (c *Canvas) Mode() int {
+	return c.mode
+}
+
+// Size returns the size of the canvas in cells.
+func (c *Canvas) Size() (rows, cols int) {
+	return c.rows, c.cols
+}
+
+// PixelSize returns the size of the canvas in pixels.
+func (c *Canvas) PixelSize() (rows, cols int) {
+	return c.rows * c.ph, c.cols * c.pw
+}
+
+// SetStroke sets how the pixels set afterward are drawn.
+func (c *Canvas) SetStroke(s Stroke) {
+	c.stroke = s
+}
+
+// Stroke returns how the pixels are drawn.
+func (c *Canvas) Stroke() Stroke {
+	return c.stroke
+}
+
+// in reports whether the pixel is on the canvas.
+func (c *Canvas) in(x, y int) bool {
+	return x >= 0 && y >= 0 && x < c.rows*c.ph && y < c.cols*c.pw
+}
+
+// cell returns the index of the cell of the pixel.
+func (c *Canvas) cell(x, y int) int {
+	return x/c.ph*c.cols + y/c.pw
+}
+
+// Set sets the pixel with the stroke.
+func (c *Canvas) Set(x, y int) {
+	if !c.in(x, y) {
+		return
+	}
+	i := x*c.cols*c.pw + y
+	c.pixels[i] = true
+	if c.mode == HalfBlock {
+		c.colors[i] = c.stroke.Color
+	} else {
+		c.colors[c.cell(x, y)] = c.stroke.Color
+	}
+	if c.mode == Cell {
+		c.chars[c.cell(x, y)] = c.stroke.Char
+	}
+}
+
+// Unset clears the pixel.
+func (c *Canvas) Unset(x, y int) {
+	if c.in(x, y) {
+		c.pixels[x*c.cols*c.pw+y] = false
+	}
+}
+
+// Toggle sets the pixel if it is clear, otherwise clears it.
+func (c *Canvas) Toggle(x, y int) {
+	if c.Get(x, y) {
+		c.Unset(x, y)
+	} else {
+		c.Set(x, y)
+	}
+}
+
+// Get reports whether the pixel is set, pixels outside the canvas are clear.
+func (c *Canvas) Get(x, y int) bool {
+	return c.in(x, y) && c.pixels[x*c.cols*c.pw+y]
+}
+
+// Clear clears all the pixels.
+func (c *Canvas) Clear() {
+	clear(c.pixels)
+	clear(c.colors)
+	clear(c.chars)
+}
+
+// brailleDots are the bits of the dots of a braille character, by row and column
+var brailleDots = [4][2]rune{{0x01, 0x08}, {0x02, 0x10}, {0x04, 0x20}, {0x40, 0x80}}
+
+// glyph returns the character of the cell and its style.
+func (c *Canvas) glyph(row, col int) (ch rune, style []int) {
+	x, y := row*c.ph, col*c.pw
+	switch c.mode {
+	case Braille:
+		var dots rune
+		for i := 0; i < 4; i++ {
+			for j := 0; j < 2; j++ {
+				if c.Get(x+i, y+j) {
+					dots |= brailleDots[i][j]
+				}
+			}
+		}
+		if dots == 0 {
+			return ' ', nil
+		}
+		return 0x2800 + dots, colorStyle(c.colors[c.cell(x, y)])
+	case HalfBlock:
+		upper, lower := c.Get(x, y), c.Get(x+1, y)
+		top, bottom := c.colors[x*c.cols+y], 0
+		if x+1 < c.rows*c.ph {
+			bottom = c.colors[(x+1)*c.cols+y]
+		}
+		switch {
+		case upper && lower && top == bottom:
+			return '█', colorStyle(top)
+		case upper && lower && bottom == font.RESET: // the default color can only be a foreground
+			return '▄', []int{font.BgColor(top)}
+		case upper && lower:
+			return '▀', append(colorStyle(top), font.BgColor(bottom))
+		case upper:
+			return '▀', colorStyle(top)
+		case lower:
+			return '▄', colorStyle(bottom)
+		}
+		return ' ', nil
+	default:
+		if !c.Get(x, y) {
+			return ' ', nil
+		}
+		ch = c.chars[c.cell(x, y)]
+		if ch == 0 {
+			ch = '█'
+		}
+		return ch, colorStyle(c.colors[c.cell(x, y)])
+	}
+}
+
+// colorStyle returns the style of a color, nil for the default color.
+func colorStyle(color int) []int {
+	if color == font.RESET {
+		return nil
+	}
+	return []int{color}
+}
+
+// Lines returns the canvas as lines of text, a run of cells of the same style is decorated once.
+func (c *Canvas) Lines() []string {
+	lines := make([]string, c.rows)
+	for row := range lines {
+		b, run := strings.Builder{}, strings.Builder{}
+		var runStyle []int
+		flush := func() {
+			if len(runStyle) == 0 {
+				b.WriteString(run.String())
+			} else {
+				b.WriteString(font.Decorate(run.String(), runStyle...))
+			}
+			run.Reset()
+		}
+		for col := 0; col < c.cols; col++ {
+			ch, style := c.glyph(row, col)
+			if !slices.Equal(style, runStyle) {
+				flush()
+				runStyle = style
+			}
+			run.WriteRune(ch)
+		}
+		flush()
+		lines[row] = b.String()
+	}
+	return lines
+}
+
+// Print prints the canvas on the screen with its top left corner at (x, y).
+func (c *Canvas) Print(x, y int) {
+	widget.Print(c, x, y)
+}
+
+// Measure implements widget.Widget
+func (c *Canvas) Measure(cons widget.Constraints) widget.Size {
+	return cons.Fit(widget.Size{Rows: c.rows, Cols: c.cols})
+}
+
+// Render implements widget.Widget, the canvas is clipped to the region.
+func (c *Canvas) Render(r widget.Region) {
+	for i, line := range c.Lines() {
+		if i >= r.Rows {
+			break
+		}
+		r.Line(i, line)
+	}
+}
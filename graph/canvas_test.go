@@ -0,0 +1,105 @@
+package graph
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/gngtwhh/gocui/font"
+	"github.com/gngtwhh/gocui/widget"
+)
+
+// pixel is a pixel set with a color, for the tables of the canvas.
+type pixel struct {
+	x, y, color int
+}
+
+func TestCanvasLines(t *testing.T) {
+	tests := []struct {
+		name       string
+		mode       int
+		rows, cols int
+		pixels     []pixel
+		want       []string
+	}{
+		{"braille blank", Braille, 1, 2, nil, []string{"  "}},
+		{"braille dots", Braille, 1, 2, []pixel{{0, 0, 0}, {3, 1, 0}, {0, 2, 0}}, []string{"⢁⠁"}},
+		{"braille rows", Braille, 2, 1, []pixel{{1, 0, 0}, {4, 1, 0}}, []string{"⠂", "⠈"}},
+		{"braille clipped", Braille, 1, 1, []pixel{{4, 0, 0}, {0, 2, 0}, {-1, 0, 0}}, []string{" "}},
+		{"braille color", Braille, 1, 2, []pixel{{0, 0, font.Red}, {0, 1, font.Blue}, {0, 2, font.Blue}},
+			[]string{font.Decorate("⠉⠁", font.Blue)}},
+		{"half blocks", HalfBlock, 1, 4, []pixel{{0, 0, 0}, {1, 1, 0}, {0, 2, 0}, {1, 2, 0}}, []string{"▀▄█ "}},
+		{"half block colors", HalfBlock, 1, 2, []pixel{{0, 0, font.Red}, {1, 0, font.Blue}, {0, 1, font.Red}, {1, 1, 0}},
+			[]string{font.Decorate("▀", font.Red, font.BlueBg) + font.Decorate("▄", font.RedBg)}},
+		{"cells", Cell, 2, 3, []pixel{{0, 0, 0}, {1, 2, font.Green}}, []string{"█  ", "  " + font.Decorate("█", font.Green)}},
+	}
+	for _, tt := range tests {
+		c := NewCanvas(tt.rows, tt.cols, tt.mode)
+		for _, p := range tt.pixels {
+			c.SetStroke(Stroke{Color: p.color})
+			c.Set(p.x, p.y)
+		}
+		if got := c.Lines(); !slices.Equal(got, tt.want) {
+			t.Errorf("%s: lines = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCanvasPixels(t *testing.T) {
+	c := NewCanvas(2, 3, Braille)
+	if r, cols := c.PixelSize(); r != 8 || cols != 6 {
+		t.Errorf("pixel size = %d x %d, want 8 x 6", r, cols)
+	}
+	if r, cols := c.Size(); r != 2 || cols != 3 || c.Mode() != Braille {
+		t.Errorf("size = %d x %d, mode %d", r, cols, c.Mode())
+	}
+	c.Set(1, 1)
+	c.Toggle(2, 2)
+	c.Toggle(1, 1)
+	if c.Get(1, 1) || !c.Get(2, 2) || c.Get(-1, 0) || c.Get(8, 0) {
+		t.Error("Toggle or Get is wrong")
+	}
+	c.Unset(2, 2)
+	c.Unset(100, 100)
+	if c.Get(2, 2) {
+		t.Error("Unset did not clear the pixel")
+	}
+	c.Set(0, 0)
+	c.Clear()
+	if c.Get(0, 0) {
+		t.Error("Clear did not clear the pixels")
+	}
+	if m := NewCanvas(-1, 2, 42); m.Mode() != Cell {
+		t.Errorf("an unknown mode gives the mode %d, want Cell", m.Mode())
+	}
+}
+
+func TestCanvasCellChars(t *testing.T) {
+	c := NewCanvas(1, 3, Cell)
+	c.SetStroke(Stroke{Char: '*'})
+	c.Set(0, 0)
+	c.SetStroke(Stroke{Char: 'o', Color: font.Red})
+	c.Set(0, 2)
+	if s := c.Stroke(); s.Char != 'o' || s.Color != font.Red {
+		t.Errorf("stroke = %+v", s)
+	}
+	if got, want := c.Lines()[0], "* "+font.Decorate("o", font.Red); got != want {
+		t.Errorf("line = %q, want %q", got, want)
+	}
+}
+
+func TestCanvasWidget(t *testing.T) {
+	c := NewCanvas(3, 4, Cell)
+	for x := range 3 {
+		for y := range 4 {
+			c.Set(x, y)
+		}
+	}
+	if s := c.Measure(widget.Constraints{MaxRows: 2, MaxCols: 10}); s != (widget.Size{Rows: 2, Cols: 4}) {
+		t.Errorf("Measure = %+v, want 2 x 4", s)
+	}
+	buf := widget.NewBuffer(2, 3)
+	c.Render(buf.Region())
+	if got := buf.Lines(); !slices.Equal(got, []string{"███", "███"}) {
+		t.Errorf("rendered %q, want the canvas clipped to the region", got)
+	}
+}
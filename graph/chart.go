@@ -0,0 +1,300 @@
+package graph
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/gngtwhh/gocui/font"
+	"github.com/gngtwhh/gocui/widget"
+)
+
+// Bar is a bar of a bar chart, a bar of several values is stacked.
+type Bar struct {
+	Label  string
+	Values []float64
+}
+
+// BarChart is a widget of labelled bars, drawn on a canvas with eighths of a cell for precision.
+type BarChart struct {
+	Rows, Cols int     // Rows, Cols: the size, 0 to fill the region, a vertical chart is 10 rows high by default
+	Horizontal bool    // Horizontal: whether the bars grow to the right instead of up
+	Max        float64 // Max: the value of a full bar, 0 to scale to the largest bar
+	BarWidth   int     // BarWidth: the columns of a vertical bar, 0 for the width of the labels
+	Gap        int     // Gap: the cells between the bars
+	ShowValues bool    // ShowValues: whether the total of each bar is shown
+	Colors     []int   // Colors: the colors of the stacked values, default Palette
+
+	bars []Bar
+}
+
+// NewBarChart creates an empty vertical bar chart, with a gap of 1 between the bars.
+func NewBarChart() *BarChart {
+	return &BarChart{Gap: 1}
+}
+
+// Add adds a bar, stacked if there are several values.
+func (b *BarChart) Add(label string, values ...float64) *BarChart {
+	b.bars = append(b.bars, Bar{Label: label, Values: values})
+	return b
+}
+
+// Histogram creates a bar chart of the number of the samples in each of bins ranges of the same width,
+// from the smallest to the largest sample. The bars are labelled with the start of their ranges.
+func Histogram(samples []float64, bins int) *BarChart {
+	b := NewBarChart()
+	bins = max(bins, 1)
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, s := range samples {
+		if !math.IsNaN(s) && !math.IsInf(s, 0) {
+			lo, hi = min(lo, s), max(hi, s)
+		}
+	}
+	if lo > hi {
+		return b
+	}
+	width := (hi - lo) / float64(bins)
+	counts := make([]float64, bins)
+	for _, s := range samples {
+		if math.IsNaN(s) || math.IsInf(s, 0) {
+			continue
+		}
+		i := bins - 1
+		if width > 0 {
+			i = min(int((s-lo)/width), bins-1)
+		}
+		counts[i]++
+	}
+	for i, n := range counts {
+		b.Add(strconv.FormatFloat(lo+width*float64(i), 'g', 3, 64), n)
+	}
+	return b
+}
+
+// Measure implements widget.Widget
+func (b *BarChart) Measure(c widget.Constraints) widget.Size {
+	s := widget.Size{Rows: b.Rows, Cols: b.Cols}
+	if s.Rows <= 0 {
+		if b.Horizontal {
+			s.Rows = max(len(b.bars)*(1+b.Gap)-b.Gap, 0)
+		} else {
+			s.Rows = 10
+		}
+	}
+	if s.Cols <= 0 {
+		if b.Horizontal {
+			s.Cols = c.MaxCols
+		} else {
+			n := len(b.bars)
+			s.Cols = max(n*b.barWidth()+(n-1)*b.Gap, 0)
+		}
+	}
+	return c.Fit(s)
+}
+
+// Render implements widget.Widget
+func (b *BarChart) Render(r widget.Region) {
+	for i, line := range b.Lines(r.Rows, r.Cols) {
+		r.Line(i, line)
+	}
+}
+
+// Print prints the chart on the screen with its top left corner at (x, y).
+func (b *BarChart) Print(x, y int) {
+	widget.Print(b, x, y)
+}
+
+// Lines returns the chart of rows x cols cells as lines of text.
+func (b *BarChart) Lines(rows, cols int) []string {
+	if b.Horizontal {
+		return b.horizontal(rows, cols)
+	}
+	return b.vertical(rows, cols)
+}
+
+// horizontal returns the lines of a horizontal chart: the labels, the bars and the values.
+func (b *BarChart) horizontal(rows, cols int) []string {
+	labelW, valueW := 0, 0
+	for _, bar := range b.bars {
+		labelW = max(labelW, font.Width(bar.Label))
+		valueW = max(valueW, len(formatValue(total(bar.Values))))
+	}
+	length := cols - labelW - 1 // the length of a full bar
+	if b.ShowValues {
+		length -= valueW + 1
+	}
+	if length <= 0 || rows <= 0 {
+		return nil
+	}
+	c := NewCanvas(rows, length, Cell)
+	labels := make([]string, rows)
+	values := make([]string, rows)
+	scale := float64(length) / b.max()
+	for i, bar := range b.bars {
+		row := i * (1 + b.Gap)
+		if row >= rows {
+			break
+		}
+		labels[row] = bar.Label
+		values[row] = formatValue(total(bar.Values))
+		b.stack(bar.Values, scale, func(seg, from, to int, partial int) {
+			c.SetStroke(Stroke{Char: '█', Color: b.color(seg)})
+			c.FillRect(row, from, 1, to-from)
+			if partial > 0 {
+				c.SetStroke(Stroke{Char: font.LeftEighths[partial], Color: b.color(seg)})
+				c.Set(row, to)
+			}
+		})
+	}
+	lines := c.Lines()
+	for i := range lines {
+		lines[i] = font.Pad(labels[i], labelW) + " " + lines[i]
+		if b.ShowValues {
+			lines[i] += " " + values[i]
+		}
+	}
+	return lines
+}
+
+// vertical returns the lines of a vertical chart: the bars, the labels and the values below.
+func (b *BarChart) vertical(rows, cols int) []string {
+	textRows := 1
+	if b.ShowValues {
+		textRows++
+	}
+	height, barW := rows-textRows, b.barWidth()
+	if height <= 0 || cols <= 0 {
+		return nil
+	}
+	c := NewCanvas(height, cols, Cell)
+	labels, values := strings.Builder{}, strings.Builder{}
+	scale := float64(height) / b.max()
+	for i, bar := range b.bars {
+		col := i * (barW + b.Gap)
+		if col+barW > cols {
+			break
+		}
+		if i > 0 {
+			labels.WriteString(strings.Repeat(" ", b.Gap))
+			values.WriteString(strings.Repeat(" ", b.Gap))
+		}
+		labels.WriteString(font.Center(font.Strip(bar.Label), barW))
+		values.WriteString(font.Center(formatValue(total(bar.Values)), barW))
+		b.stack(bar.Values, scale, func(seg, from, to int, partial int) {
+			c.SetStroke(Stroke{Char: '█', Color: b.color(seg)})
+			c.FillRect(height-to, col, to-from, barW)
+			if partial > 0 {
+				c.SetStroke(Stroke{Char: font.LowerEighths[partial], Color: b.color(seg)})
+				c.FillRect(height-to-1, col, 1, barW)
+			}
+		})
+	}
+	lines := append(c.Lines(), font.Pad(labels.String(), cols))
+	if b.ShowValues {
+		lines = append(lines, font.Pad(values.String(), cols))
+	}
+	return lines
+}
+
+// stack calls draw for each segment of the stacked values from the base of the bar,
+// with the cells [from, to) it covers, and the eighths of the cell after them for the last segment.
+func (b *BarChart) stack(values []float64, scale float64, draw func(seg, from, to, partial int)) {
+	var sum float64
+	from := 0
+	for seg, v := range values {
+		sum += max(v, 0)
+		end := sum * scale
+		if seg < len(values)-1 {
+			to := int(math.Round(end))
+			draw(seg, from, to, 0)
+			from = to
+			continue
+		}
+		eighths := int(math.Round(end * 8))
+		draw(seg, from, max(eighths/8, from), eighths%8)
+	}
+}
+
+// max returns the value of a full bar.
+func (b *BarChart) max() float64 {
+	if b.Max > 0 {
+		return b.Max
+	}
+	m := 0.0
+	for _, bar := range b.bars {
+		m = max(m, total(bar.Values))
+	}
+	if m <= 0 {
+		return 1
+	}
+	return m
+}
+
+// barWidth returns the columns of a vertical bar.
+func (b *BarChart) barWidth() int {
+	if b.BarWidth > 0 {
+		return b.BarWidth
+	}
+	w := 1
+	for _, bar := range b.bars {
+		w = max(w, font.Width(bar.Label))
+		if b.ShowValues {
+			w = max(w, len(formatValue(total(bar.Values))))
+		}
+	}
+	return w
+}
+
+// color returns the color of the seg-th stacked value.
+func (b *BarChart) color(seg int) int {
+	colors := b.Colors
+	if len(colors) == 0 {
+		colors = Palette
+	}
+	return colors[seg%len(colors)]
+}
+
+// total returns the sum of the positive values.
+func total(values []float64) float64 {
+	var sum float64
+	for _, v := range values {
+		sum += max(v, 0)
+	}
+	return sum
+}
+
+// formatValue formats a value without trailing zeros, with at most 2 decimals.
+func formatValue(v float64) string {
+	s := strconv.FormatFloat(v, 'f', 2, 64)
+	return strings.TrimSuffix(strings.TrimRight(s, "0"), ".")
+}
+
+// sparks are the bars of a sparkline, from the lowest to the highest
+var sparks = font.LowerEighths[1:]
+
+// Sparkline returns the values as a compact line of bars, one cell per value,
+// scaled from the smallest to the largest value, NaN is shown as a space.
+// If width > 0, only the last width values are shown.
+func Sparkline(values []float64, width int) string {
+	if width > 0 && len(values) > width {
+		values = values[len(values)-width:]
+	}
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, v := range values {
+		if !math.IsNaN(v) && !math.IsInf(v, 0) {
+			lo, hi = min(lo, v), max(hi, v)
+		}
+	}
+	line := make([]rune, len(values))
+	for i, v := range values {
+		switch {
+		case math.IsNaN(v) || math.IsInf(v, 0):
+			line[i] = ' '
+		case hi == lo:
+			line[i] = sparks[0]
+		default:
+			line[i] = sparks[int(math.Round((v-lo)/(hi-lo)*float64(len(sparks)-1)))]
+		}
+	}
+	return string(line)
+}
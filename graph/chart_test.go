@@ -0,0 +1,77 @@
+package graph
+
+import (
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/gngtwhh/gocui/font"
+)
+
+func TestSparkline(t *testing.T) {
+	nan := math.NaN()
+	tests := []struct {
+		values []float64
+		width  int
+		want   string
+	}{
+		{nil, 0, ""},
+		{[]float64{0, 1, 2, 3, 4, 5, 6, 7}, 0, "▁▂▃▄▅▆▇█"},
+		{[]float64{-7, 0}, 0, "▁█"},
+		{[]float64{3, 3, 3}, 0, "▁▁▁"},
+		{[]float64{0, nan, 7, math.Inf(1)}, 0, "▁ █ "},
+		{[]float64{0, 1, 2, 3, 4, 5, 6, 7}, 3, "▁▅█"},
+		{[]float64{0, 1}, 5, "▁█"},
+		{[]float64{0, 3.4, 3.6, 7}, 0, "▁▄▅█"},
+	}
+	for _, tt := range tests {
+		if got := Sparkline(tt.values, tt.width); got != tt.want {
+			t.Errorf("Sparkline(%v, %d) = %q, want %q", tt.values, tt.width, got, tt.want)
+		}
+	}
+}
+
+func TestHistogram(t *testing.T) {
+	tests := []struct {
+		samples []float64
+		bins    int
+		labels  []string
+		counts  []float64
+	}{
+		{nil, 3, nil, nil},
+		{[]float64{math.NaN()}, 3, nil, nil},
+		{[]float64{0, 1, 2, 3, 4, 5}, 3, []string{"0", "1.67", "3.33"}, []float64{2, 2, 2}},
+		{[]float64{0, 10, 10, 9.9}, 2, []string{"0", "5"}, []float64{1, 3}},
+		{[]float64{2, 2, math.Inf(1)}, 2, []string{"2", "2"}, []float64{0, 2}},
+		{[]float64{1, 2}, 0, []string{"1"}, []float64{2}},
+	}
+	for _, tt := range tests {
+		var labels []string
+		var counts []float64
+		for _, bar := range Histogram(tt.samples, tt.bins).bars {
+			labels = append(labels, bar.Label)
+			counts = append(counts, bar.Values...)
+		}
+		if !slices.Equal(labels, tt.labels) || !slices.Equal(counts, tt.counts) {
+			t.Errorf("Histogram(%v, %d) = %q %v, want %q %v", tt.samples, tt.bins, labels, counts, tt.labels, tt.counts)
+		}
+	}
+}
+
+func TestBarChartLines(t *testing.T) {
+	b := NewBarChart().Add("北京", 8).Add("ab", 4)
+	b.ShowValues = true
+	lines := b.Lines(4, 9)
+	want := []string{"████     ", "████ ████", "北京  ab ", " 8    4  "}
+	for i := range lines {
+		lines[i] = font.Strip(lines[i])
+	}
+	if !slices.Equal(lines, want) {
+		t.Errorf("vertical lines = %q, want %q", lines, want)
+	}
+	for _, line := range lines {
+		if w := font.Width(line); w != 9 {
+			t.Errorf("line %q is %d columns wide, want 9", line, w)
+		}
+	}
+}
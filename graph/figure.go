@@ -0,0 +1,76 @@
+package graph
+
+import "github.com/gngtwhh/gocui/widget"
+
+// Figure is a widget made of lines and curves.
+// The coordinates are relative to the region the figure is rendered in,
+// x is the row and y is the column as in Line and Curve, points outside the region are clipped.
+type Figure struct {
+	points []point
+}
+
+type point struct {
+	x, y int
+	ch   rune
+}
+
+// NewFigure creates an empty figure.
+func NewFigure() *Figure {
+	return &Figure{}
+}
+
+// Line adds a line to the figure, the params are the same as Line.
+func (f *Figure) Line(x, y, length int, ch rune, lineType uint8) *Figure {
+	for i := 0; i < length; i++ {
+		if lineType == 0 {
+			f.points = append(f.points, point{x + i, y, ch})
+		} else {
+			f.points = append(f.points, point{x, y + i, ch})
+		}
+	}
+	return f
+}
+
+// Curve adds a curve to the figure, the params are the same as Curve.
+func (f *Figure) Curve(x, y, length, sign int, ch rune, fn func(int) int) *Figure {
+	from, to := 0, length
+	if sign < 0 {
+		from, to = -length, 0
+	}
+	for i := from; i < to; i++ {
+		f.points = append(f.points, point{x + i, y + fn(i), ch})
+	}
+	return f
+}
+
+// Measure implements widget.Widget
+func (f *Figure) Measure(c widget.Constraints) widget.Size {
+	var s widget.Size
+	for _, p := range f.points {
+		s.Rows = max(s.Rows, p.x+1)
+		s.Cols = max(s.Cols, p.y+1)
+	}
+	return c.Fit(s)
+}
+
+// Render implements widget.Widget
+func (f *Figure) Render(r widget.Region) {
+	if r.Rows <= 0 || r.Cols <= 0 {
+		return
+	}
+	grid := make([][]rune, r.Rows)
+	for i := range grid {
+		grid[i] = make([]rune, r.Cols)
+		for j := range grid[i] {
+			grid[i][j] = ' '
+		}
+	}
+	for _, p := range f.points {
+		if p.x >= 0 && p.x < r.Rows && p.y >= 0 && p.y < r.Cols {
+			grid[p.x][p.y] = p.ch
+		}
+	}
+	for i, row := range grid {
+		r.Line(i, string(row))
+	}
+}
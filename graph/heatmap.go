@@ -0,0 +1,280 @@
+package graph
+
+import (
+	"image/color"
+	"math"
+	"strings"
+
+	"github.com/gngtwhh/gocui/font"
+	"github.com/gngtwhh/gocui/widget"
+)
+
+// ColorScale is a gradient of colors spaced evenly from 0 to 1, interpolated linearly between them.
+type ColorScale []color.RGBA
+
+// Color scales of heatmaps: sequential scales grow from low to high values,
+// diverging scales grow both ways from a neutral color in the middle.
+var (
+	Viridis = ColorScale{
+		{0x44, 0x01, 0x54, 0xff}, {0x48, 0x28, 0x78, 0xff}, {0x3e, 0x4a, 0x89, 0xff}, {0x31, 0x68, 0x8e, 0xff},
+		{0x26, 0x82, 0x8e, 0xff}, {0x1f, 0x9e, 0x89, 0xff}, {0x35, 0xb7, 0x79, 0xff}, {0x6d, 0xcd, 0x59, 0xff},
+		{0xb4, 0xde, 0x2c, 0xff}, {0xfd, 0xe7, 0x25, 0xff},
+	}
+	Inferno = ColorScale{
+		{0x00, 0x00, 0x04, 0xff}, {0x42, 0x0a, 0x68, 0xff}, {0x93, 0x26, 0x67, 0xff},
+		{0xdd, 0x51, 0x3a, 0xff}, {0xfc, 0xa5, 0x0a, 0xff}, {0xfc, 0xff, 0xa4, 0xff},
+	}
+	Grays  = ColorScale{{0x10, 0x10, 0x10, 0xff}, {0xf0, 0xf0, 0xf0, 0xff}}
+	Greens = ColorScale{
+		{0xf7, 0xfc, 0xf5, 0xff}, {0xc7, 0xe9, 0xc0, 0xff}, {0x74, 0xc4, 0x76, 0xff},
+		{0x23, 0x8b, 0x45, 0xff}, {0x00, 0x44, 0x1b, 0xff},
+	}
+	BlueRed = ColorScale{
+		{0x21, 0x66, 0xac, 0xff}, {0x67, 0xa9, 0xcf, 0xff}, {0xd1, 0xe5, 0xf0, 0xff}, {0xf7, 0xf7, 0xf7, 0xff},
+		{0xfd, 0xdb, 0xc7, 0xff}, {0xef, 0x8a, 0x62, 0xff}, {0xb2, 0x18, 0x2b, 0xff},
+	}
+	PurpleGreen = ColorScale{
+		{0x76, 0x2a, 0x83, 0xff}, {0xaf, 0x8d, 0xc3, 0xff}, {0xe7, 0xd4, 0xe8, 0xff}, {0xf7, 0xf7, 0xf7, 0xff},
+		{0xd9, 0xf0, 0xd3, 0xff}, {0x7f, 0xbf, 0x7b, 0xff}, {0x1b, 0x78, 0x37, 0xff},
+	}
+)
+
+// At returns the color of the scale at t in [0, 1], t is clamped.
+func (s ColorScale) At(t float64) color.RGBA {
+	switch {
+	case len(s) == 0:
+		return color.RGBA{}
+	case len(s) == 1 || t <= 0 || math.IsNaN(t):
+		return s[0]
+	case t >= 1:
+		return s[len(s)-1]
+	}
+	pos := t * float64(len(s)-1)
+	i := int(pos)
+	f := pos - float64(i)
+	a, b := s[i], s[i+1]
+	mix := func(x, y uint8) uint8 { return uint8(math.Round(float64(x) + (float64(y)-float64(x))*f)) }
+	return color.RGBA{mix(a.R, b.R), mix(a.G, b.G), mix(a.B, b.B), 0xff}
+}
+
+// Color returns the color of the scale at t as a color of font, see font.RGB.
+func (s ColorScale) Color(t float64) int {
+	c := s.At(t)
+	return font.RGB(c.R, c.G, c.B)
+}
+
+// Heatmap is a widget drawing a matrix of values as colored cells on a canvas,
+// with optional labels of the rows and the columns, and a legend of the scale.
+// True colors are output as the closest colors of the terminal, see font.ColorLevel.
+type Heatmap struct {
+	Rows, Cols int        // Rows, Cols: the size, 0 for a value per cell, or per half a cell with HalfBlock
+	Scale      ColorScale // Scale: the colors from the lowest to the highest value, default Viridis
+	Min, Max   float64    // Min, Max: the values at the ends of the scale, auto-scaled to the data if equal
+	Diverging  bool       // Diverging: whether Center is in the middle of the scale, for a scale such as BlueRed
+	Center     float64    // Center: the neutral value of a diverging scale
+	HalfBlock  bool       // HalfBlock: whether two rows of values are drawn in a row of cells
+	CellWidth  int        // CellWidth: the columns of a value, default 2
+	RowLabels  []string   // RowLabels: the labels on the left of the rows
+	ColLabels  []string   // ColLabels: the labels below the columns
+	Legend     bool       // Legend: whether the scale is shown below with its range
+
+	data [][]float64
+}
+
+// NewHeatmap creates a heatmap of the matrix, data[i][j] is the value of the row i and the column j.
+// NaN values are left blank.
+func NewHeatmap(data [][]float64) *Heatmap {
+	return &Heatmap{Scale: Viridis, CellWidth: 2, data: data}
+}
+
+// SetData replaces the matrix of the heatmap.
+func (h *Heatmap) SetData(data [][]float64) *Heatmap {
+	h.data = data
+	return h
+}
+
+// Labels sets the labels of the rows and the columns.
+func (h *Heatmap) Labels(rows, cols []string) *Heatmap {
+	h.RowLabels, h.ColLabels = rows, cols
+	return h
+}
+
+// size returns the number of rows and columns of the matrix.
+func (h *Heatmap) size() (rows, cols int) {
+	for _, row := range h.data {
+		cols = max(cols, len(row))
+	}
+	return len(h.data), cols
+}
+
+// labelWidth returns the width of the row labels, including the space after them.
+func (h *Heatmap) labelWidth() int {
+	w := 0
+	for _, l := range h.RowLabels {
+		w = max(w, font.Width(l))
+	}
+	if w > 0 {
+		w++
+	}
+	return w
+}
+
+// textRows returns the rows below the cells, for the column labels and the legend.
+func (h *Heatmap) textRows() int {
+	n := 0
+	if len(h.ColLabels) > 0 {
+		n++
+	}
+	if h.Legend {
+		n++
+	}
+	return n
+}
+
+// Measure implements widget.Widget
+func (h *Heatmap) Measure(c widget.Constraints) widget.Size {
+	nr, nc := h.size()
+	s := widget.Size{Rows: h.Rows, Cols: h.Cols}
+	if s.Rows <= 0 {
+		s.Rows = nr
+		if h.HalfBlock {
+			s.Rows = (nr + 1) / 2
+		}
+		s.Rows += h.textRows()
+	}
+	if s.Cols <= 0 {
+		s.Cols = h.labelWidth() + nc*max(h.CellWidth, 1)
+	}
+	return c.Fit(s)
+}
+
+// Render implements widget.Widget
+func (h *Heatmap) Render(r widget.Region) {
+	for i, line := range h.Lines(r.Rows, r.Cols) {
+		r.Line(i, line)
+	}
+}
+
+// Print prints the heatmap on the screen with its top left corner at (x, y).
+func (h *Heatmap) Print(x, y int) {
+	widget.Print(h, x, y)
+}
+
+// Lines returns the heatmap of rows x cols cells as lines of text.
+// The matrix is stretched or shrunk to the cells left by the labels and the legend.
+func (h *Heatmap) Lines(rows, cols int) []string {
+	nr, nc := h.size()
+	labelW := h.labelWidth()
+	height, width := rows-h.textRows(), cols-labelW
+	if nr == 0 || nc == 0 || height <= 0 || width <= 0 {
+		return nil
+	}
+	mode := Cell
+	if h.HalfBlock {
+		mode = HalfBlock
+	}
+	c := NewCanvas(height, width, mode)
+	pr, pc := c.PixelSize()
+	lo, hi := h.scale()
+	for x := 0; x < pr; x++ {
+		i := x * nr / pr
+		for y := 0; y < pc; y++ {
+			j := y * nc / pc
+			if j >= len(h.data[i]) || math.IsNaN(h.data[i][j]) {
+				continue
+			}
+			c.SetStroke(Stroke{Char: '█', Color: h.colorOf(h.data[i][j], lo, hi)})
+			c.Set(x, y)
+		}
+	}
+
+	lines := c.Lines()
+	for k := range lines {
+		if labelW == 0 {
+			break
+		}
+		label := "" // the label of the row at the top of the line, once if the row spans several lines
+		if i := k * c.ph * nr / pr; i < len(h.RowLabels) && (k == 0 || i != (k-1)*c.ph*nr/pr) {
+			label = font.Truncate(h.RowLabels[i], labelW-1)
+		}
+		lines[k] = strings.Repeat(" ", labelW-1-font.Width(label)) + label + " " + lines[k]
+	}
+	if len(h.ColLabels) > 0 {
+		lines = append(lines, strings.Repeat(" ", labelW)+h.colLabels(nc, width))
+	}
+	if h.Legend {
+		lines = append(lines, strings.Repeat(" ", labelW)+h.legend(lo, hi, width))
+	}
+	return lines
+}
+
+// colLabels returns the labels of the columns centered below them, skipping those overlapping the previous one.
+func (h *Heatmap) colLabels(nc, width int) string {
+	line := strings.Builder{}
+	col, end := 0, 0 // the columns written, and the end of the last label to keep a space between labels
+	for j := 0; j < min(nc, len(h.ColLabels)); j++ {
+		label := font.Strip(h.ColLabels[j])
+		lw := font.Width(label)
+		from, to := j*width/nc, (j+1)*width/nc
+		start := max(from+(to-from-lw)/2, 0)
+		if start < end || start+lw > width {
+			continue
+		}
+		line.WriteString(strings.Repeat(" ", start-col) + label)
+		col = start + lw
+		end = col + 1
+	}
+	return line.String()
+}
+
+// legend returns the scale from lo to hi in width columns, between the labels of lo and hi.
+func (h *Heatmap) legend(lo, hi float64, width int) string {
+	left, right := formatValue(lo), formatValue(hi)
+	n := width - len(left) - len(right) - 2
+	if n < 2 {
+		return font.Truncate(left+" "+right, width)
+	}
+	b := strings.Builder{}
+	for k := 0; k < n; k++ {
+		b.WriteString(font.Decorate("█", h.scaleOf().Color(float64(k)/float64(n-1))))
+	}
+	return left + " " + b.String() + " " + right
+}
+
+// scale returns the values at the ends of the scale, auto-scaled to the data if not set.
+// The range of a diverging scale is symmetric around Center.
+func (h *Heatmap) scale() (lo, hi float64) {
+	lo, hi = min(h.Min, h.Max), max(h.Min, h.Max)
+	if lo == hi {
+		lo, hi = math.Inf(1), math.Inf(-1)
+		for _, row := range h.data {
+			for _, v := range row {
+				if !math.IsNaN(v) && !math.IsInf(v, 0) {
+					lo, hi = min(lo, v), max(hi, v)
+				}
+			}
+		}
+		lo, hi = widen(lo, hi, 0, 1)
+	}
+	if h.Diverging {
+		d := max(math.Abs(lo-h.Center), math.Abs(hi-h.Center))
+		if d == 0 {
+			d = 1
+		}
+		lo, hi = h.Center-d, h.Center+d
+	}
+	return lo, hi
+}
+
+// colorOf returns the color of the value v on the scale from lo to hi.
+func (h *Heatmap) colorOf(v, lo, hi float64) int {
+	return h.scaleOf().Color((v - lo) / (hi - lo))
+}
+
+// scaleOf returns the color scale of the heatmap.
+func (h *Heatmap) scaleOf() ColorScale {
+	if len(h.Scale) == 0 {
+		return Viridis
+	}
+	return h.Scale
+}
@@ -0,0 +1,124 @@
+package graph
+
+import (
+	"image/color"
+	"math"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/gngtwhh/gocui/font"
+	"github.com/gngtwhh/gocui/widget"
+)
+
+func TestColorScaleAt(t *testing.T) {
+	s := ColorScale{{0, 0, 0, 0xff}, {100, 200, 50, 0xff}, {200, 0, 250, 0xff}}
+	tests := []struct {
+		t    float64
+		want color.RGBA
+	}{
+		{-1, s[0]},
+		{0, s[0]},
+		{math.NaN(), s[0]},
+		{0.25, color.RGBA{50, 100, 25, 0xff}},
+		{0.5, s[1]},
+		{0.75, color.RGBA{150, 100, 150, 0xff}},
+		{1, s[2]},
+		{2, s[2]},
+	}
+	for _, tt := range tests {
+		if got := s.At(tt.t); got != tt.want {
+			t.Errorf("At(%v) = %v, want %v", tt.t, got, tt.want)
+		}
+	}
+	if got := (ColorScale{}).At(0.5); got != (color.RGBA{}) {
+		t.Errorf("At of an empty scale = %v", got)
+	}
+}
+
+func TestHeatmapScale(t *testing.T) {
+	data := [][]float64{{1, 2}, {math.NaN(), 5}}
+	tests := []struct {
+		name   string
+		h      *Heatmap
+		lo, hi float64
+	}{
+		{"auto", NewHeatmap(data), 1, 5},
+		{"fixed", &Heatmap{Min: 10, Max: 0, data: data}, 0, 10},
+		{"diverging", &Heatmap{Diverging: true, Center: 2, data: data}, -1, 5},
+	}
+	for _, tt := range tests {
+		if lo, hi := tt.h.scale(); lo != tt.lo || hi != tt.hi {
+			t.Errorf("%s: scale = %v, %v, want %v, %v", tt.name, lo, hi, tt.lo, tt.hi)
+		}
+	}
+}
+
+func TestHeatmapLines(t *testing.T) {
+	gray := ColorScale{{0, 0, 0, 0xff}, {0xff, 0xff, 0xff, 0xff}}
+	data := [][]float64{{0, 1}, {1, math.NaN()}}
+	tests := []struct {
+		name       string
+		h          *Heatmap
+		rows, cols int
+		want       []string
+	}{
+		{"cells", &Heatmap{Scale: gray, CellWidth: 2, data: data}, 2, 4, []string{
+			font.Decorate("██", font.RGB(0, 0, 0)) + font.Decorate("██", font.RGB(0xff, 0xff, 0xff)),
+			font.Decorate("██", font.RGB(0xff, 0xff, 0xff)) + "  ",
+		}},
+		{"labels", (&Heatmap{CellWidth: 2, data: data}).Labels([]string{"a", "bb"}, []string{"x", "y"}), 3, 7, []string{
+			" a ████", "bb ██  ", "   x y",
+		}},
+		{"overlapping labels", (&Heatmap{data: data}).Labels(nil, []string{"long", "y"}), 3, 4, []string{
+			"████", "██  ", "long",
+		}},
+		{"legend", &Heatmap{Legend: true, data: data}, 3, 10, []string{
+			"██████████", "█████     ", "0 ██████ 1",
+		}},
+		{"too small", &Heatmap{Legend: true, data: data}, 1, 10, nil},
+		{"no data", &Heatmap{}, 2, 2, nil},
+	}
+	for _, tt := range tests {
+		got := tt.h.Lines(tt.rows, tt.cols)
+		if tt.name != "cells" {
+			for i := range got {
+				got[i] = font.Strip(got[i])
+			}
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%s: Lines = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestHeatmapMeasure(t *testing.T) {
+	data := [][]float64{{1, 2, 3}, {4, 5, 6}, {7, 8, 9}}
+	tests := []struct {
+		name string
+		h    *Heatmap
+		want widget.Size
+	}{
+		{"cells", NewHeatmap(data), widget.Size{Rows: 3, Cols: 6}},
+		{"half blocks", &Heatmap{HalfBlock: true, CellWidth: 1, data: data}, widget.Size{Rows: 2, Cols: 3}},
+		{"labels and legend", NewHeatmap(data).Labels([]string{"row"}, []string{"c"}), widget.Size{Rows: 4, Cols: 10}},
+		{"fixed", &Heatmap{Rows: 5, Cols: 8, data: data}, widget.Size{Rows: 5, Cols: 8}},
+		{"fit", &Heatmap{Rows: 50, Cols: 80, data: data}, widget.Size{Rows: 20, Cols: 40}},
+	}
+	for _, tt := range tests {
+		if got := tt.h.Measure(widget.Constraints{MaxRows: 20, MaxCols: 40}); got != tt.want {
+			t.Errorf("%s: Measure = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+	if lines := NewHeatmap(data).Lines(3, 6); strings.Contains(strings.Join(lines, ""), " ") {
+		t.Errorf("a full matrix leaves blank cells: %q", lines)
+	}
+}
+
+func TestHeatmapWideLabels(t *testing.T) {
+	h := (&Heatmap{CellWidth: 4, data: [][]float64{{1, 2}}}).Labels(nil, []string{"北京", "y"})
+	lines := h.Lines(2, 8)
+	if got, want := font.Strip(lines[1]), "北京 y"; got != want {
+		t.Errorf("labels = %q, want %q", got, want)
+	}
+}
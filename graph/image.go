@@ -0,0 +1,329 @@
+package graph
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"strings"
+
+	"github.com/gngtwhh/gocui/cursor"
+	"github.com/gngtwhh/gocui/font"
+	"github.com/gngtwhh/gocui/utils"
+	"github.com/gngtwhh/gocui/widget"
+)
+
+// The protocols of a picture printed on the screen
+const (
+	Blocks = iota // half-block characters in colors, supported everywhere
+	Sixel         // the Sixel graphics of xterm, mlterm, foot, WezTerm and others
+	Kitty         // the graphics protocol of kitty, also supported by WezTerm and Ghostty
+)
+
+// Picture is a widget of an image drawn with half blocks, a cell shows two pixels in true colors,
+// or in the closest colors of the terminal with dithering, see font.ColorLevel.
+// The image is resized to fit the size of the widget, keeping its aspect ratio.
+type Picture struct {
+	Rows, Cols int  // Rows, Cols: the maximum size, 0 for the size of the image, a pixel per column and half a row
+	Dither     bool // Dither: whether the colors are dithered if the terminal has fewer colors than true colors
+	Protocol   int  // Protocol: how the picture is printed by Print, default Blocks, the widget always uses Blocks
+
+	// CellWidth, CellHeight: the pixels of a cell of the terminal for Sixel, default 10 x 20
+	CellWidth, CellHeight int
+
+	img image.Image
+}
+
+// NewPicture creates a picture of the image with dithering.
+func NewPicture(img image.Image) *Picture {
+	return &Picture{Dither: true, img: img}
+}
+
+// Image returns the image drawn with half blocks in w x h cells at most as lines of text,
+// 0 leaves a side free, keeping the aspect ratio.
+func Image(img image.Image, w, h int) []string {
+	p := NewPicture(img)
+	p.Rows, p.Cols = h, w
+	rows, cols := p.size(h, w)
+	return p.Lines(rows, cols)
+}
+
+// DetectProtocol returns the graphics protocol of the terminal guessed from the environment, Blocks if unknown.
+// Sixel cannot be detected without querying the terminal, it is only returned for known terminals.
+func DetectProtocol() int {
+	term, program := os.Getenv("TERM"), os.Getenv("TERM_PROGRAM")
+	switch {
+	case os.Getenv("KITTY_WINDOW_ID") != "" || term == "xterm-kitty" || term == "xterm-ghostty" ||
+		program == "WezTerm" || program == "ghostty":
+		return Kitty
+	case term == "foot" || strings.HasPrefix(term, "mlterm") || program == "mintty":
+		return Sixel
+	}
+	return Blocks
+}
+
+// size returns the cells of the image fitting rows x cols, 0 leaves a side free.
+func (p *Picture) size(rows, cols int) (int, int) {
+	if p.img == nil {
+		return 0, 0
+	}
+	w, h := p.pixels(rows*2, cols)
+	return (h + 1) / 2, w
+}
+
+// pixels returns the size of the image resized to fit height x width pixels, keeping its aspect ratio.
+// 0 leaves a side free, if both are 0 the image is not resized.
+func (p *Picture) pixels(height, width int) (w, h int) {
+	b := p.img.Bounds()
+	w, h = b.Dx(), b.Dy()
+	if w <= 0 || h <= 0 {
+		return 0, 0
+	}
+	scale := 1.0
+	switch {
+	case width > 0 && height > 0:
+		scale = min(float64(width)/float64(w), float64(height)/float64(h))
+	case width > 0:
+		scale = float64(width) / float64(w)
+	case height > 0:
+		scale = float64(height) / float64(h)
+	}
+	return max(int(float64(w)*scale+0.5), 1), max(int(float64(h)*scale+0.5), 1)
+}
+
+// Measure implements widget.Widget
+func (p *Picture) Measure(c widget.Constraints) widget.Size {
+	rows, cols := p.size(p.Rows, p.Cols)
+	return c.Fit(widget.Size{Rows: rows, Cols: cols})
+}
+
+// Render implements widget.Widget
+func (p *Picture) Render(r widget.Region) {
+	for i, line := range p.Lines(r.Rows, r.Cols) {
+		r.Line(i, line)
+	}
+}
+
+// Print prints the picture on the screen with its top left corner at (x, y), by the protocol of the picture.
+func (p *Picture) Print(x, y int) {
+	var seq string
+	switch p.Protocol {
+	case Sixel:
+		seq = p.Sixel(p.size(p.Rows, p.Cols))
+	case Kitty:
+		seq = p.Kitty(p.size(p.Rows, p.Cols))
+	default:
+		widget.Print(p, x, y)
+		return
+	}
+	utils.ConsoleMutex.Lock()
+	defer utils.ConsoleMutex.Unlock()
+	fmt.Print(cursor.Around(cursor.GotoXYSeq(x, y) + seq))
+}
+
+// Lines returns the picture of rows x cols cells at most as lines of half blocks,
+// transparent pixels are left blank.
+func (p *Picture) Lines(rows, cols int) []string {
+	if p.img == nil || rows <= 0 || cols <= 0 {
+		return nil
+	}
+	w, h := p.pixels(rows*2, cols)
+	pixels := resize(p.img, w, h)
+	c := NewCanvas((h+1)/2, w, HalfBlock)
+	colors := p.quantize(pixels, w, h)
+	for i := 0; i < h; i++ {
+		for j := 0; j < w; j++ {
+			if pixels[i*w+j].A < 0x80 {
+				continue
+			}
+			c.SetStroke(Stroke{Color: colors[i*w+j]})
+			c.Set(i, j)
+		}
+	}
+	return c.Lines()
+}
+
+// quantize returns the colors of font of the pixels. If the terminal has fewer colors than true colors
+// and Dither is set, the pixels are dithered to the colors of the terminal by Floyd-Steinberg.
+func (p *Picture) quantize(pixels []color.RGBA, w, h int) []int {
+	colors := make([]int, len(pixels))
+	if !p.Dither || font.ColorLevel >= font.LevelTrueColor {
+		for i, px := range pixels {
+			colors[i] = font.RGB(px.R, px.G, px.B)
+		}
+		return colors
+	}
+	// the errors are diffused in a buffer of floats to the pixels on the right and below
+	buf := make([][3]float64, len(pixels))
+	for i, px := range pixels {
+		buf[i] = [3]float64{float64(px.R), float64(px.G), float64(px.B)}
+	}
+	clamp := func(v float64) uint8 { return uint8(min(max(v+0.5, 0), 255)) }
+	spread := func(i, j int, e [3]float64, f float64) {
+		if i < h && j >= 0 && j < w {
+			for k := range e {
+				buf[i*w+j][k] += e[k] * f
+			}
+		}
+	}
+	for i := 0; i < h; i++ {
+		for j := 0; j < w; j++ {
+			v := buf[i*w+j]
+			r, g, b := clamp(v[0]), clamp(v[1]), clamp(v[2])
+			var c int
+			if font.ColorLevel >= font.Level256 {
+				c = font.Color256(font.RGBTo256(r, g, b))
+			} else {
+				c = font.RGBTo16(r, g, b)
+			}
+			colors[i*w+j] = c
+			qr, qg, qb, _ := font.RGBOf(c)
+			e := [3]float64{v[0] - float64(qr), v[1] - float64(qg), v[2] - float64(qb)}
+			spread(i, j+1, e, 7.0/16)
+			spread(i+1, j-1, e, 3.0/16)
+			spread(i+1, j, e, 5.0/16)
+			spread(i+1, j+1, e, 1.0/16)
+		}
+	}
+	return colors
+}
+
+// resize returns the pixels of the image resized to w x h by row, averaging the area of each pixel.
+func resize(img image.Image, w, h int) []color.RGBA {
+	b := img.Bounds()
+	pixels := make([]color.RGBA, w*h)
+	for i := 0; i < h; i++ {
+		y0 := b.Min.Y + i*b.Dy()/h
+		y1 := max(b.Min.Y+(i+1)*b.Dy()/h, y0+1)
+		for j := 0; j < w; j++ {
+			x0 := b.Min.X + j*b.Dx()/w
+			x1 := max(b.Min.X+(j+1)*b.Dx()/w, x0+1)
+			var r, g, bl, a, n uint64
+			for y := y0; y < y1; y++ {
+				for x := x0; x < x1; x++ {
+					cr, cg, cb, ca := img.At(x, y).RGBA()
+					r, g, bl, a, n = r+uint64(cr), g+uint64(cg), bl+uint64(cb), a+uint64(ca), n+1
+				}
+			}
+			// the colors are premultiplied by alpha, divide them back for the color of opaque pixels
+			if a == 0 {
+				continue
+			}
+			pixels[i*w+j] = color.RGBA{
+				R: uint8(min(r*0xff/a, 0xff)), G: uint8(min(g*0xff/a, 0xff)), B: uint8(min(bl*0xff/a, 0xff)),
+				A: uint8(a / n >> 8),
+			}
+		}
+	}
+	return pixels
+}
+
+// Kitty returns the escape sequence drawing the image in rows x cols cells by the graphics protocol of kitty,
+// at the cursor. The image is sent as PNG and scaled by the terminal.
+func (p *Picture) Kitty(rows, cols int) string {
+	if p.img == nil {
+		return ""
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, p.img); err != nil {
+		return ""
+	}
+	data := base64.StdEncoding.EncodeToString(buf.Bytes())
+	const chunk = 4096
+	b := strings.Builder{}
+	for i := 0; i < len(data); i += chunk {
+		more := 0
+		if i+chunk < len(data) {
+			more = 1
+		}
+		if i == 0 {
+			fmt.Fprintf(&b, "\033_Ga=T,f=100,q=2,c=%d,r=%d,m=%d;", cols, rows, more)
+		} else {
+			fmt.Fprintf(&b, "\033_Gm=%d;", more)
+		}
+		b.WriteString(data[i:min(i+chunk, len(data))])
+		b.WriteString("\033\\")
+	}
+	return b.String()
+}
+
+// Sixel returns the escape sequence drawing the image in rows x cols cells by the Sixel graphics, at the cursor.
+// The image is resized to the pixels of the cells, see CellWidth and CellHeight,
+// with the colors of the 256-color palette.
+func (p *Picture) Sixel(rows, cols int) string {
+	if p.img == nil || rows <= 0 || cols <= 0 {
+		return ""
+	}
+	cw, ch := p.CellWidth, p.CellHeight
+	if cw <= 0 || ch <= 0 {
+		cw, ch = 10, 20
+	}
+	w, h := p.pixels(rows*ch, cols*cw)
+	pixels := resize(p.img, w, h)
+	index := make([]int, len(pixels)) // the color of each pixel in the palette, -1 if transparent
+	used := make(map[int]bool)
+	for i, px := range pixels {
+		index[i] = -1
+		if px.A >= 0x80 {
+			index[i] = int(font.RGBTo256(px.R, px.G, px.B))
+			used[index[i]] = true
+		}
+	}
+
+	b := strings.Builder{}
+	fmt.Fprintf(&b, "\033P0;1;0q\"1;1;%d;%d", w, h)
+	for n := 0; n < 256; n++ {
+		if used[n] {
+			r, g, bl, _ := font.RGBOf(font.Color256(uint8(n)))
+			fmt.Fprintf(&b, "#%d;2;%d;%d;%d", n, int(r)*100/255, int(g)*100/255, int(bl)*100/255)
+		}
+	}
+	// the image is drawn in bands of 6 rows, a band is drawn once per color it contains
+	for top := 0; top < h; top += 6 {
+		colors := make(map[int][]byte)
+		var order []int
+		for j := 0; j < w; j++ {
+			for k := 0; k < 6 && top+k < h; k++ {
+				n := index[(top+k)*w+j]
+				if n < 0 {
+					continue
+				}
+				if colors[n] == nil {
+					colors[n] = make([]byte, w)
+					order = append(order, n)
+				}
+				colors[n][j] |= 1 << k
+			}
+		}
+		for i, n := range order {
+			if i > 0 {
+				b.WriteByte('$') // back to the start of the band
+			}
+			fmt.Fprintf(&b, "#%d", n)
+			writeSixels(&b, colors[n])
+		}
+		b.WriteByte('-') // the next band
+	}
+	b.WriteString("\033\\")
+	return b.String()
+}
+
+// writeSixels writes the sixels of a band of a color, with runs of the same sixel compressed.
+func writeSixels(b *strings.Builder, bits []byte) {
+	for j := 0; j < len(bits); {
+		k := j
+		for k < len(bits) && bits[k] == bits[j] {
+			k++
+		}
+		ch := byte('?' + bits[j])
+		if n := k - j; n > 3 {
+			fmt.Fprintf(b, "!%d%c", n, ch)
+		} else {
+			b.WriteString(strings.Repeat(string(ch), n))
+		}
+		j = k
+	}
+}
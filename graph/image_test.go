@@ -0,0 +1,138 @@
+package graph
+
+import (
+	"image"
+	"image/color"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/gngtwhh/gocui/font"
+	"github.com/gngtwhh/gocui/widget"
+)
+
+// checker returns a w x h image of black and white pixels, with the pixel at (0, 0) transparent.
+func checker(w, h int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			if (x+y)%2 == 0 {
+				img.Set(x, y, color.White)
+			} else {
+				img.Set(x, y, color.Black)
+			}
+		}
+	}
+	img.Set(0, 0, color.Transparent)
+	return img
+}
+
+func TestPictureSize(t *testing.T) {
+	img := checker(40, 20)
+	tests := []struct {
+		name       string
+		rows, cols int
+		want       widget.Size
+	}{
+		{"image size", 0, 0, widget.Size{Rows: 10, Cols: 40}},
+		{"width", 0, 20, widget.Size{Rows: 5, Cols: 20}},
+		{"height", 2, 0, widget.Size{Rows: 2, Cols: 8}},
+		{"both", 5, 4, widget.Size{Rows: 1, Cols: 4}},
+		{"clipped", 100, 100, widget.Size{Rows: 25, Cols: 50}},
+	}
+	for _, tt := range tests {
+		p := NewPicture(img)
+		p.Rows, p.Cols = tt.rows, tt.cols
+		if got := p.Measure(widget.Constraints{MaxRows: 50, MaxCols: 50}); got != tt.want {
+			t.Errorf("%s: Measure = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+	if got := (&Picture{}).Measure(widget.Constraints{MaxRows: 5, MaxCols: 5}); got != (widget.Size{}) {
+		t.Errorf("Measure without an image = %+v", got)
+	}
+}
+
+func TestImage(t *testing.T) {
+	level := font.ColorLevel
+	font.ColorLevel = font.LevelTrueColor
+	defer func() { font.ColorLevel = level }()
+
+	white, black := font.RGB(0xff, 0xff, 0xff), font.RGB(0, 0, 0)
+	got := Image(checker(2, 2), 0, 0)
+	want := []string{font.Decorate("▄", black) + font.Decorate("▀", black, font.BgColor(white))}
+	if !slices.Equal(got, want) {
+		t.Errorf("Image = %q, want %q", got, want)
+	}
+	if got := Image(checker(8, 8), 2, 0); len(got) != 1 || font.Width(got[0]) != 2 {
+		t.Errorf("Image shrunk to 2 columns = %q", got)
+	}
+	if got := (&Picture{}).Lines(2, 2); got != nil {
+		t.Errorf("Lines without an image = %q", got)
+	}
+}
+
+func TestResize(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 2, 1))
+	img.Set(0, 0, color.RGBA{0xff, 0, 0, 0xff})
+	img.Set(1, 0, color.RGBA{0, 0, 0xff, 0xff})
+	if got := resize(img, 1, 1); got[0] != (color.RGBA{0x7f, 0, 0x7f, 0xff}) {
+		t.Errorf("resize averages to %v", got[0])
+	}
+	img.Set(1, 0, color.Transparent)
+	if got := resize(img, 1, 1); got[0] != (color.RGBA{0xff, 0, 0, 0x7f}) {
+		t.Errorf("resize with a transparent pixel = %v", got[0])
+	}
+}
+
+func TestKitty(t *testing.T) {
+	p := NewPicture(checker(4, 4))
+	got := p.Kitty(2, 4)
+	if !strings.HasPrefix(got, "\033_Ga=T,f=100,q=2,c=4,r=2,m=0;") || !strings.HasSuffix(got, "\033\\") {
+		t.Errorf("Kitty = %q", got)
+	}
+	big := image.NewRGBA(image.Rect(0, 0, 64, 64))
+	seed := uint32(1)
+	for i := range big.Pix {
+		seed = seed*1664525 + 1013904223 // noise, so that the PNG does not fit a chunk
+		big.Pix[i] = uint8(seed >> 24)
+	}
+	got = NewPicture(big).Kitty(4, 8)
+	if n := strings.Count(got, "\033_G"); n < 2 || !strings.Contains(got, "m=1;") || !strings.Contains(got, "\033_Gm=0;") {
+		t.Errorf("Kitty of a large image is sent in %d chunks", n)
+	}
+	if got := (&Picture{}).Kitty(1, 1); got != "" {
+		t.Errorf("Kitty without an image = %q", got)
+	}
+}
+
+func TestSixel(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 1, 1))
+	img.Set(0, 0, color.RGBA{0xff, 0, 0, 0xff})
+	p := NewPicture(img)
+	p.CellWidth, p.CellHeight = 4, 6
+	want := "\033P0;1;0q\"1;1;4;4#196;2;100;0;0#196!4N-\033\\"
+	if got := p.Sixel(1, 1); got != want {
+		t.Errorf("Sixel = %q, want %q", got, want)
+	}
+	if got := p.Sixel(0, 1); got != "" {
+		t.Errorf("Sixel of no cells = %q", got)
+	}
+}
+
+func TestWriteSixels(t *testing.T) {
+	tests := []struct {
+		bits []byte
+		want string
+	}{
+		{[]byte{1, 1, 1}, "@@@"},
+		{[]byte{1, 1, 1, 1}, "!4@"},
+		{[]byte{0, 63, 63, 2}, "?~~A"},
+	}
+	for _, tt := range tests {
+		b := strings.Builder{}
+		writeSixels(&b, tt.bits)
+		if got := b.String(); got != tt.want {
+			t.Errorf("writeSixels(%v) = %q, want %q", tt.bits, got, tt.want)
+		}
+	}
+}
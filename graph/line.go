@@ -6,10 +6,12 @@ import (
 	"github.com/gngtwhh/gocui/utils"
 )
 
-// Line Draws a line
-// x, y - starting point
+// Line Draws a straight line of length characters from (x, y)
+// x, y - starting point, x is the row and y is the column
 // length - length of the line
-// ch - character to draw, 0 - horizontal, 1 - vertical
+// ch - character to draw
+// lineType - 0: along x, down the rows(vertical), 1: along y, across the columns(horizontal)
+// Use Canvas.Line for lines at any angle.
 func Line(x, y, length int, ch rune, lineType uint8) {
 	utils.ConsoleMutex.Lock()
 	defer utils.ConsoleMutex.Unlock()
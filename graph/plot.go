@@ -0,0 +1,299 @@
+package graph
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/gngtwhh/gocui/font"
+	"github.com/gngtwhh/gocui/widget"
+)
+
+// Palette is the colors given in order to the series without a color.
+var Palette = []int{font.Green, font.Red, font.Blue, font.Yellow, font.Magenta, font.Cyan}
+
+// Series is a curve of a plot, either a function or points connected in order.
+type Series struct {
+	Name  string                // Name: shown in the legend, a series without a name is not in the legend
+	Color int                   // Color: the color of the curve, default the next color of Palette
+	Fn    func(float64) float64 // Fn: the function, sampled at each pixel column
+	X, Y  []float64             // X, Y: the points, used if Fn is nil
+}
+
+// Plot is a widget plotting series of a float domain and range onto a canvas, with labelled axes and a legend.
+// Unlike the rest of the package, X is the horizontal axis of the values, as in math.
+type Plot struct {
+	Rows, Cols int     // Rows, Cols: the size including the axes and the legend, 0 to fill the region
+	XMin, XMax float64 // XMin, XMax: the domain, auto-scaled to the points if equal, default [-1, 1] for functions
+	YMin, YMax float64 // YMin, YMax: the range, auto-scaled to the data if equal
+	XTicks     int     // XTicks: the approximate number of ticks on the x axis, default 5
+	YTicks     int     // YTicks: the approximate number of ticks on the y axis, default 4
+	Mode       int     // Mode: the mode of the canvas, default Braille
+
+	series []Series
+}
+
+// NewPlot creates an empty plot filling the region it is rendered in.
+func NewPlot() *Plot {
+	return &Plot{XTicks: 5, YTicks: 4, Mode: Braille}
+}
+
+// Domain sets the domain of the plot, x from min to max.
+func (p *Plot) Domain(min, max float64) *Plot {
+	p.XMin, p.XMax = min, max
+	return p
+}
+
+// Range sets the range of the plot, y from min to max.
+func (p *Plot) Range(min, max float64) *Plot {
+	p.YMin, p.YMax = min, max
+	return p
+}
+
+// Func adds the function f as a series, color 0 for the next color of Palette.
+func (p *Plot) Func(name string, color int, f func(float64) float64) *Plot {
+	return p.Add(Series{Name: name, Color: color, Fn: f})
+}
+
+// Points adds the points (xs[i], ys[i]) connected in order as a series, color 0 for the next color of Palette.
+func (p *Plot) Points(name string, color int, xs, ys []float64) *Plot {
+	return p.Add(Series{Name: name, Color: color, X: xs, Y: ys})
+}
+
+// Add adds a series.
+func (p *Plot) Add(s Series) *Plot {
+	if s.Color == font.RESET {
+		s.Color = Palette[len(p.series)%len(Palette)]
+	}
+	p.series = append(p.series, s)
+	return p
+}
+
+// Measure implements widget.Widget
+func (p *Plot) Measure(c widget.Constraints) widget.Size {
+	s := widget.Size{Rows: p.Rows, Cols: p.Cols}
+	if s.Rows <= 0 {
+		s.Rows = c.MaxRows
+	}
+	if s.Cols <= 0 {
+		s.Cols = c.MaxCols
+	}
+	return c.Fit(s)
+}
+
+// Render implements widget.Widget
+func (p *Plot) Render(r widget.Region) {
+	for i, line := range p.Lines(r.Rows, r.Cols) {
+		r.Line(i, line)
+	}
+}
+
+// Print prints the plot on the screen with its top left corner at (x, y).
+func (p *Plot) Print(x, y int) {
+	widget.Print(p, x, y)
+}
+
+// Lines returns the plot of rows x cols cells as lines of text.
+func (p *Plot) Lines(rows, cols int) []string {
+	lines := make([]string, 0, rows)
+	if legend := p.legend(); legend != "" && rows > 3 {
+		lines = append(lines, font.Truncate(legend, cols))
+		rows--
+	}
+	xmin, xmax := p.domain()
+	ymin, ymax := p.scale(xmin, xmax)
+	yTicks := ticks(ymin, ymax, max(p.YTicks, 2))
+	xTicks := ticks(xmin, xmax, max(p.XTicks, 2))
+
+	labelW := 0
+	for _, t := range yTicks {
+		labelW = max(labelW, len(t.label))
+	}
+	ph, pw := rows-2, cols-labelW-1 // the size of the canvas, below are the x axis and its labels
+	if ph <= 0 || pw <= 0 {
+		return lines
+	}
+	c := NewCanvas(ph, pw, p.Mode)
+	pr, pc := c.PixelSize()
+	toCol := func(x float64) float64 { return (x - xmin) / (xmax - xmin) * float64(pc-1) }
+	toRow := func(y float64) float64 { return (ymax - y) / (ymax - ymin) * float64(pr-1) }
+	for _, s := range p.series {
+		c.SetStroke(Stroke{Char: '•', Color: s.Color})
+		var prev *Point
+		plot := func(x, y float64) {
+			if math.IsNaN(y) || math.IsInf(y, 0) || math.IsNaN(x) {
+				prev = nil
+				return
+			}
+			pt := Point{clampPixel(toRow(y)), clampPixel(toCol(x))}
+			if prev != nil {
+				c.Line(prev.X, prev.Y, pt.X, pt.Y)
+			} else {
+				c.Set(pt.X, pt.Y)
+			}
+			prev = &pt
+		}
+		if s.Fn != nil {
+			for col := 0; col < pc; col++ {
+				x := xmin + float64(col)/float64(max(pc-1, 1))*(xmax-xmin)
+				plot(x, s.Fn(x))
+			}
+		} else {
+			for i := 0; i < min(len(s.X), len(s.Y)); i++ {
+				plot(s.X[i], s.Y[i])
+			}
+		}
+	}
+
+	// the y axis and its labels on the left of the canvas
+	yLabels := make([]string, ph)
+	for _, t := range yTicks {
+		if row := int(math.Round(toRow(t.value))) / (pr / ph); row >= 0 && row < ph {
+			yLabels[row] = t.label
+		}
+	}
+	for i, line := range c.Lines() {
+		axis := "│"
+		if yLabels[i] != "" {
+			axis = "┤"
+		}
+		lines = append(lines, strings.Repeat(" ", labelW-len(yLabels[i]))+yLabels[i]+axis+line)
+	}
+
+	// the x axis and its labels below the canvas
+	axis := []rune(strings.Repeat("─", pw))
+	labels := []rune(strings.Repeat(" ", cols))
+	end := 0 // the end of the last label, to keep a space between labels
+	for _, t := range xTicks {
+		col := int(math.Round(toCol(t.value))) / (pc / pw)
+		if col < 0 || col >= pw {
+			continue
+		}
+		axis[col] = '┬'
+		start := min(max(labelW+1+col-len(t.label)/2, 0), cols-len(t.label))
+		if start < end || start < 0 {
+			continue
+		}
+		copy(labels[start:], []rune(t.label))
+		end = start + len(t.label) + 1
+	}
+	lines = append(lines, strings.Repeat(" ", labelW)+"└"+string(axis), strings.TrimRight(string(labels), " "))
+	return lines
+}
+
+// legend returns the names of the series in their colors.
+func (p *Plot) legend() string {
+	var items []string
+	for _, s := range p.series {
+		if s.Name != "" {
+			items = append(items, font.Decorate("━━", s.Color)+" "+s.Name)
+		}
+	}
+	return strings.Join(items, "  ")
+}
+
+// domain returns the domain of the plot, auto-scaled to the points if not set.
+func (p *Plot) domain() (lo, hi float64) {
+	if p.XMin != p.XMax {
+		return min(p.XMin, p.XMax), max(p.XMin, p.XMax)
+	}
+	lo, hi = math.Inf(1), math.Inf(-1)
+	for _, s := range p.series {
+		if s.Fn != nil {
+			continue
+		}
+		for _, x := range s.X {
+			if !math.IsNaN(x) && !math.IsInf(x, 0) {
+				lo, hi = min(lo, x), max(hi, x)
+			}
+		}
+	}
+	return widen(lo, hi, -1, 1)
+}
+
+// scale returns the range of the plot, auto-scaled to the data over the domain if not set.
+func (p *Plot) scale(xmin, xmax float64) (lo, hi float64) {
+	if p.YMin != p.YMax {
+		return min(p.YMin, p.YMax), max(p.YMin, p.YMax)
+	}
+	lo, hi = math.Inf(1), math.Inf(-1)
+	add := func(y float64) {
+		if !math.IsNaN(y) && !math.IsInf(y, 0) {
+			lo, hi = min(lo, y), max(hi, y)
+		}
+	}
+	for _, s := range p.series {
+		if s.Fn != nil {
+			const samples = 200
+			for i := 0; i <= samples; i++ {
+				add(s.Fn(xmin + (xmax-xmin)*float64(i)/samples))
+			}
+			continue
+		}
+		for i := 0; i < min(len(s.X), len(s.Y)); i++ {
+			if s.X[i] >= xmin && s.X[i] <= xmax {
+				add(s.Y[i])
+			}
+		}
+	}
+	return widen(lo, hi, 0, 1)
+}
+
+// widen returns [lo, hi], or [defLo, defHi] if there is no data, widened if lo equals hi.
+func widen(lo, hi, defLo, defHi float64) (float64, float64) {
+	switch {
+	case lo > hi:
+		return defLo, defHi
+	case lo == hi:
+		return lo - 1, hi + 1
+	}
+	return lo, hi
+}
+
+// tick is a labelled value on an axis.
+type tick struct {
+	value float64
+	label string
+}
+
+// ticks returns about n ticks of round values in [lo, hi].
+func ticks(lo, hi float64, n int) []tick {
+	step := niceStep((hi - lo) / float64(n))
+	prec := max(0, -int(math.Floor(math.Log10(step))))
+	start := math.Ceil(lo/step) * step
+	var ts []tick
+	// the values are counted by an index, a step below the precision of large values would not move them
+	for k := 0; k <= 4*max(n, 1); k++ {
+		v := start + float64(k)*step
+		if v > hi+step*1e-9 || len(ts) > 0 && v <= ts[len(ts)-1].value {
+			break
+		}
+		if math.Abs(v) < step*1e-9 {
+			v = 0 // avoid "-0"
+		}
+		ts = append(ts, tick{v, strconv.FormatFloat(v, 'f', prec, 64)})
+	}
+	return ts
+}
+
+// niceStep returns the round step of 1, 2 or 5 times a power of 10 closest to step.
+func niceStep(step float64) float64 {
+	if step <= 0 || math.IsNaN(step) || math.IsInf(step, 0) {
+		return 1
+	}
+	base := math.Pow(10, math.Floor(math.Log10(step)))
+	switch f := step / base; {
+	case f < 1.5:
+		return base
+	case f < 3.5:
+		return 2 * base
+	case f < 7.5:
+		return 5 * base
+	}
+	return 10 * base
+}
+
+// clampPixel rounds a pixel coordinate, keeping far away values in the range of int.
+func clampPixel(v float64) int {
+	return int(math.Round(max(min(v, 1e4), -1e4)))
+}
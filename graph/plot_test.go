@@ -0,0 +1,62 @@
+package graph
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestNiceStep(t *testing.T) {
+	tests := []struct{ step, want float64 }{
+		{1, 1},
+		{1.4, 1},
+		{1.5, 2},
+		{3.4, 2},
+		{3.5, 5},
+		{7.4, 5},
+		{7.5, 10},
+		{0.03, 0.02},
+		{250, 200},
+		{0, 1},
+		{-2, 1},
+		{math.NaN(), 1},
+		{math.Inf(1), 1},
+	}
+	for _, tt := range tests {
+		if got := niceStep(tt.step); math.Abs(got-tt.want) > tt.want*1e-12 {
+			t.Errorf("niceStep(%v) = %v, want %v", tt.step, got, tt.want)
+		}
+	}
+}
+
+func TestTicks(t *testing.T) {
+	tests := []struct {
+		lo, hi float64
+		n      int
+		want   []string
+	}{
+		{0, 10, 5, []string{"0", "2", "4", "6", "8", "10"}},
+		{-1, 1, 4, []string{"-1.0", "-0.5", "0.0", "0.5", "1.0"}},
+		{0.3, 1.7, 2, []string{"0.5", "1.0", "1.5"}},
+		{-3, -1, 2, []string{"-3", "-2", "-1"}},
+		{0, 1000, 4, []string{"0", "200", "400", "600", "800", "1000"}},
+		{1e17, 1e17 + 10, 4, []string{"100000000000000000"}},
+		{5, 5, 4, []string{"5"}},
+	}
+	for _, tt := range tests {
+		var labels []string
+		for _, tk := range ticks(tt.lo, tt.hi, tt.n) {
+			labels = append(labels, tk.label)
+		}
+		if !slices.Equal(labels, tt.want) {
+			t.Errorf("ticks(%v, %v, %d) = %q, want %q", tt.lo, tt.hi, tt.n, labels, tt.want)
+		}
+	}
+}
+
+func TestPlotLargeRange(t *testing.T) {
+	p := NewPlot().Range(1e17, 1e17+10).Func("", 0, func(x float64) float64 { return 1e17 })
+	if lines := p.Lines(10, 40); len(lines) != 10 {
+		t.Errorf("the plot has %d lines, want 10", len(lines))
+	}
+}
@@ -0,0 +1,312 @@
+package graph
+
+import (
+	"math"
+	"slices"
+)
+
+// Point is a pixel of a canvas, X is the row and Y is the column.
+type Point struct {
+	X, Y int
+}
+
+// Box-drawing characters of BoxRect: horizontal, vertical, top left, top right, bottom left, bottom right
+var (
+	FineBox    = []rune{'─', '│', '┌', '┐', '└', '┘'}
+	BoldBox    = []rune{'━', '┃', '┏', '┓', '┗', '┛'}
+	DoubleBox  = []rune{'═', '║', '╔', '╗', '╚', '╝'}
+	RoundedBox = []rune{'─', '│', '╭', '╮', '╰', '╯'}
+)
+
+// All the shapes are drawn with the stroke of the canvas, set it by SetStroke before drawing a shape
+// to select the character and the color of the shape.
+
+// Line draws a line from (x0, y0) to (x1, y1) at any angle, by Bresenham's algorithm.
+// The line is clipped to the canvas first, so the points may be far outside of it.
+func (c *Canvas) Line(x0, y0, x1, y1 int) {
+	x0, y0, x1, y1, ok := c.clip(x0, y0, x1, y1)
+	if !ok {
+		return
+	}
+	dx, dy := abs(x1-x0), -abs(y1-y0)
+	sx, sy := sign(x1-x0), sign(y1-y0)
+	e := dx + dy
+	for {
+		c.Set(x0, y0)
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+		e2 := 2 * e
+		if e2 >= dy {
+			e += dy
+			x0 += sx
+		}
+		if e2 <= dx {
+			e += dx
+			y0 += sy
+		}
+	}
+}
+
+// clip returns the part of the line from (x0, y0) to (x1, y1) inside the canvas, by the Liang–Barsky algorithm,
+// and false if there is none. A line inside the canvas is returned as is to keep its exact pixels.
+func (c *Canvas) clip(x0, y0, x1, y1 int) (cx0, cy0, cx1, cy1 int, ok bool) {
+	if c.in(x0, y0) && c.in(x1, y1) {
+		return x0, y0, x1, y1, true
+	}
+	rows, cols := c.PixelSize()
+	if rows == 0 || cols == 0 {
+		return
+	}
+	fx, fy := float64(x0), float64(y0)
+	dx, dy := float64(x1)-fx, float64(y1)-fy
+	t0, t1 := 0.0, 1.0
+	// each edge as p*t <= q
+	for _, e := range [][2]float64{{-dx, fx}, {dx, float64(rows-1) - fx}, {-dy, fy}, {dy, float64(cols-1) - fy}} {
+		p, q := e[0], e[1]
+		switch {
+		case p == 0:
+			if q < 0 {
+				return // parallel to the edge and outside
+			}
+		case p < 0:
+			t0 = max(t0, q/p)
+		default:
+			t1 = min(t1, q/p)
+		}
+	}
+	if t0 > t1 {
+		return
+	}
+	return int(math.Round(fx + t0*dx)), int(math.Round(fy + t0*dy)),
+		int(math.Round(fx + t1*dx)), int(math.Round(fy + t1*dy)), true
+}
+
+// Polyline draws lines connecting the points in order.
+func (c *Canvas) Polyline(points ...Point) {
+	if len(points) == 1 {
+		c.Set(points[0].X, points[0].Y)
+	}
+	for i := 1; i < len(points); i++ {
+		c.Line(points[i-1].X, points[i-1].Y, points[i].X, points[i].Y)
+	}
+}
+
+// Polygon draws the outline of the polygon of the points, the last point is connected to the first.
+func (c *Canvas) Polygon(points ...Point) {
+	c.Polyline(points...)
+	if len(points) > 2 {
+		last := points[len(points)-1]
+		c.Line(last.X, last.Y, points[0].X, points[0].Y)
+	}
+}
+
+// FillPolygon draws the polygon of the points filled, by the even-odd rule.
+func (c *Canvas) FillPolygon(points ...Point) {
+	if len(points) < 3 {
+		c.Polyline(points...)
+		return
+	}
+	top, bottom := points[0].X, points[0].X
+	for _, p := range points {
+		top, bottom = min(top, p.X), max(bottom, p.X)
+	}
+	rows, _ := c.PixelSize()
+	var cross []float64
+	for x := max(top, 0); x <= min(bottom, rows-1); x++ {
+		cross = cross[:0]
+		for i, p := range points {
+			q := points[(i+1)%len(points)]
+			if (p.X <= x) != (q.X <= x) { // the edge crosses the row, half-open so a vertex counts once
+				cross = append(cross, float64(p.Y)+float64(x-p.X)*float64(q.Y-p.Y)/float64(q.X-p.X))
+			}
+		}
+		slices.Sort(cross)
+		for i := 0; i+1 < len(cross); i += 2 {
+			for y := int(math.Ceil(cross[i])); y <= int(math.Floor(cross[i+1])); y++ {
+				c.Set(x, y)
+			}
+		}
+	}
+	c.Polygon(points...)
+}
+
+// Rect draws the outline of the rectangle of rows x cols pixels with its top left corner at (x, y).
+func (c *Canvas) Rect(x, y, rows, cols int) {
+	if rows <= 0 || cols <= 0 {
+		return
+	}
+	c.Polygon(Point{x, y}, Point{x, y + cols - 1}, Point{x + rows - 1, y + cols - 1}, Point{x + rows - 1, y})
+}
+
+// FillRect draws the rectangle of rows x cols pixels filled, with its top left corner at (x, y).
+func (c *Canvas) FillRect(x, y, rows, cols int) {
+	for i := x; i < x+rows; i++ {
+		for j := y; j < y+cols; j++ {
+			c.Set(i, j)
+		}
+	}
+}
+
+// BoxRect draws the outline of the rectangle with the box-drawing characters, such as FineBox or RoundedBox,
+// in the Cell mode. In other modes, the characters are ignored and the outline is drawn as Rect.
+func (c *Canvas) BoxRect(x, y, rows, cols int, chars []rune) {
+	if c.mode != Cell || len(chars) < 6 {
+		c.Rect(x, y, rows, cols)
+		return
+	}
+	if rows <= 0 || cols <= 0 {
+		return
+	}
+	stroke := c.stroke
+	defer c.SetStroke(stroke)
+	draw := func(x, y int, ch rune) {
+		c.stroke.Char = ch
+		c.Set(x, y)
+	}
+	for j := y + 1; j < y+cols-1; j++ {
+		draw(x, j, chars[0])
+		draw(x+rows-1, j, chars[0])
+	}
+	for i := x + 1; i < x+rows-1; i++ {
+		draw(i, y, chars[1])
+		draw(i, y+cols-1, chars[1])
+	}
+	draw(x, y, chars[2])
+	draw(x, y+cols-1, chars[3])
+	draw(x+rows-1, y, chars[4])
+	draw(x+rows-1, y+cols-1, chars[5])
+}
+
+// Circle draws the outline of the circle with the radius r centered at (x, y).
+// Pixels of the Braille and HalfBlock modes are about square, pixels of the Cell mode are twice as tall as wide,
+// draw an ellipse with b = 2a instead to look round.
+func (c *Canvas) Circle(x, y, r int) {
+	c.Ellipse(x, y, r, r)
+}
+
+// FillCircle draws the circle with the radius r centered at (x, y) filled.
+func (c *Canvas) FillCircle(x, y, r int) {
+	c.FillEllipse(x, y, r, r)
+}
+
+// Ellipse draws the outline of the ellipse centered at (x, y),
+// with the radius a along the rows and the radius b along the columns, by the midpoint algorithm.
+func (c *Canvas) Ellipse(x, y, a, b int) {
+	if a < 0 || b < 0 {
+		return
+	}
+	if a == 0 || b == 0 {
+		c.Line(x-a, y-b, x+a, y+b)
+		return
+	}
+	plot := func(i, j int) { // i along the rows, j along the columns
+		c.Set(x+i, y+j)
+		c.Set(x+i, y-j)
+		c.Set(x-i, y+j)
+		c.Set(x-i, y-j)
+	}
+	a2, b2 := float64(a*a), float64(b*b)
+	i, j := a, 0
+	// the region where the columns change faster
+	d := a2 - b2*float64(a) + b2/4
+	for a2*float64(j) < b2*float64(i) {
+		plot(i, j)
+		j++
+		if d < 0 {
+			d += a2 * float64(2*j+1)
+		} else {
+			i--
+			d += a2*float64(2*j+1) - 2*b2*float64(i)
+		}
+	}
+	// the region where the rows change faster
+	d = a2*(float64(j)+0.5)*(float64(j)+0.5) + b2*float64((i-1)*(i-1)) - a2*b2
+	for i >= 0 {
+		plot(i, j)
+		i--
+		if d > 0 {
+			d += b2 * float64(1-2*i)
+		} else {
+			j++
+			d += a2*float64(2*j) + b2*float64(1-2*i)
+		}
+	}
+}
+
+// FillEllipse draws the ellipse centered at (x, y) filled,
+// with the radius a along the rows and the radius b along the columns.
+func (c *Canvas) FillEllipse(x, y, a, b int) {
+	if a < 0 || b < 0 {
+		return
+	}
+	for i := -a; i <= a; i++ {
+		w := b
+		if a > 0 {
+			w = int(math.Round(float64(b) * math.Sqrt(1-float64(i*i)/float64(a*a))))
+		}
+		for j := -w; j <= w; j++ {
+			c.Set(x+i, y+j)
+		}
+	}
+	c.Ellipse(x, y, a, b)
+}
+
+// Arc draws the arc of the ellipse centered at (x, y), with the radius a along the rows and b along the columns,
+// from the angle from to the angle to in radians. The angle 0 points to the right, and grows counterclockwise.
+func (c *Canvas) Arc(x, y, a, b int, from, to float64) {
+	if a < 0 || b < 0 {
+		return
+	}
+	if to < from {
+		from, to = to, from
+	}
+	steps := max(int((to-from)*float64(max(a, b))), 1) * 2
+	at := func(t float64) Point {
+		return Point{x - int(math.Round(float64(a)*math.Sin(t))), y + int(math.Round(float64(b)*math.Cos(t)))}
+	}
+	prev := at(from)
+	for k := 1; k <= steps; k++ {
+		p := at(from + (to-from)*float64(k)/float64(steps))
+		c.Line(prev.X, prev.Y, p.X, p.Y)
+		prev = p
+	}
+	c.Set(prev.X, prev.Y)
+}
+
+// FloodFill fills the clear pixels connected to (x, y) vertically and horizontally, within the canvas.
+// Nothing is filled if the pixel is set.
+func (c *Canvas) FloodFill(x, y int) {
+	if !c.in(x, y) || c.Get(x, y) {
+		return
+	}
+	stack := []Point{{x, y}}
+	c.Set(x, y)
+	for len(stack) > 0 {
+		p := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, n := range []Point{{p.X - 1, p.Y}, {p.X + 1, p.Y}, {p.X, p.Y - 1}, {p.X, p.Y + 1}} {
+			if c.in(n.X, n.Y) && !c.Get(n.X, n.Y) {
+				c.Set(n.X, n.Y)
+				stack = append(stack, n)
+			}
+		}
+	}
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+func sign(n int) int {
+	switch {
+	case n > 0:
+		return 1
+	case n < 0:
+		return -1
+	}
+	return 0
+}
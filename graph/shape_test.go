@@ -0,0 +1,64 @@
+package graph
+
+import (
+	"math"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// cells returns a canvas of rows x cols cells drawn by draw with the character '#'.
+func cells(rows, cols int, draw func(c *Canvas)) []string {
+	c := NewCanvas(rows, cols, Cell)
+	c.SetStroke(Stroke{Char: '#'})
+	draw(c)
+	return c.Lines()
+}
+
+func TestShapes(t *testing.T) {
+	tests := []struct {
+		name string
+		draw func(c *Canvas)
+		want string
+	}{
+		{"line", func(c *Canvas) { c.Line(0, 0, 2, 4) }, "#    |" + " ##  |" + "   ##"},
+		{"line backwards", func(c *Canvas) { c.Line(2, 4, 0, 0) }, "##   |" + "  ## |" + "    #"},
+		{"vertical line", func(c *Canvas) { c.Line(0, 1, 2, 1) }, " #   |" + " #   |" + " #   "},
+		{"clipped line", func(c *Canvas) { c.Line(-2, -2, 4, 4) }, "#    |" + " #   |" + "  #  "},
+		{"far line", func(c *Canvas) { c.Line(1, -1<<40, 1, 1<<40) }, "     |" + "#####|" + "     "},
+		{"far diagonal", func(c *Canvas) { c.Line(-1<<40, -1<<40, 1<<40, 1<<40) }, "#    |" + " #   |" + "  #  "},
+		{"line outside", func(c *Canvas) { c.Line(-1, -5, -1<<40, 1<<40) }, "     |" + "     |" + "     "},
+		{"polyline", func(c *Canvas) { c.Polyline(Point{0, 0}, Point{0, 2}, Point{2, 2}) }, "###  |" + "  #  |" + "  #  "},
+		{"single point", func(c *Canvas) { c.Polyline(Point{1, 3}) }, "     |" + "   # |" + "     "},
+		{"polygon", func(c *Canvas) { c.Polygon(Point{0, 0}, Point{0, 4}, Point{2, 0}) }, "#####|" + "# ## |" + "##   "},
+		{"rect", func(c *Canvas) { c.Rect(0, 1, 3, 4) }, " ####|" + " #  #|" + " ####"},
+		{"empty rect", func(c *Canvas) { c.Rect(0, 0, 0, 3) }, "     |" + "     |" + "     "},
+		{"fill rect", func(c *Canvas) { c.FillRect(1, 1, 2, 2) }, "     |" + " ##  |" + " ##  "},
+		{"box rect", func(c *Canvas) { c.BoxRect(0, 0, 3, 4, RoundedBox) }, "╭──╮ |" + "│  │ |" + "╰──╯ "},
+		{"fill polygon", func(c *Canvas) { c.FillPolygon(Point{0, 2}, Point{2, 0}, Point{2, 4}) }, "  #  |" + " ### |" + "#####"},
+		{"flood fill", func(c *Canvas) { c.Rect(0, 0, 3, 5); c.Unset(1, 0); c.FloodFill(1, 2) }, "#####|" + "#####|" + "#####"},
+		{"flood fill on a set pixel", func(c *Canvas) { c.Set(1, 1); c.FloodFill(1, 1) }, "     |" + " #   |" + "     "},
+		{"flat ellipse", func(c *Canvas) { c.Ellipse(1, 2, 0, 2) }, "     |" + "#####|" + "     "},
+		{"circle", func(c *Canvas) { c.Circle(1, 2, 1) }, "  #  |" + " # # |" + "  #  "},
+		{"fill circle", func(c *Canvas) { c.FillCircle(1, 2, 1) }, "  #  |" + " ### |" + "  #  "},
+		{"arc", func(c *Canvas) { c.Arc(2, 0, 2, 4, 0, math.Pi/2) }, "###  |" + "   ##|" + "    #"},
+	}
+	for _, tt := range tests {
+		got := strings.Join(cells(3, 5, tt.draw), "|")
+		if got != tt.want {
+			t.Errorf("%s:\n%s\nwant\n%s", tt.name, strings.ReplaceAll(got, "|", "\n"), strings.ReplaceAll(tt.want, "|", "\n"))
+		}
+	}
+}
+
+func TestBoxRectStroke(t *testing.T) {
+	c := NewCanvas(2, 2, Cell)
+	c.SetStroke(Stroke{Char: 'x'})
+	c.BoxRect(0, 0, 2, 2, FineBox)
+	if c.Stroke().Char != 'x' {
+		t.Errorf("the stroke changed to %q", c.Stroke().Char)
+	}
+	if got := c.Lines(); !slices.Equal(got, []string{"┌┐", "└┘"}) {
+		t.Errorf("lines = %q", got)
+	}
+}
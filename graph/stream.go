@@ -0,0 +1,298 @@
+package graph
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/gngtwhh/gocui/font"
+	"github.com/gngtwhh/gocui/live"
+	"github.com/gngtwhh/gocui/widget"
+	"github.com/gngtwhh/gocui/window"
+)
+
+// StreamChart is a live time-series chart of a rolling window.
+// Points are pushed from any goroutine into bounded ring buffers, and the chart is redrawn at a fixed frame rate,
+// in a live region at the cursor by default, so it coexists with running progress bars.
+type StreamChart struct {
+	Rows, Cols int           // Rows, Cols: the size, 0 for 12 rows and the width of the terminal
+	Window     time.Duration // Window: the time shown, 0 to show all the points kept
+	Period     time.Duration // Period: the time between two frames, default 100ms
+	Mode       int           // Mode: the mode of the canvas, default Braille
+	ShowStats  bool          // ShowStats: whether the min, max and average of each series are shown below
+
+	mu       sync.Mutex
+	capacity int
+	series   []*stream
+	bindPos  bool
+	posX     int
+	posY     int
+	stop     chan struct{}
+	done     chan struct{}
+	region   *live.Region
+}
+
+// stream is a series of a stream chart, the points are kept in a ring buffer.
+type stream struct {
+	name   string
+	color  int
+	ts     []time.Time
+	vs     []float64
+	start  int // index of the oldest point
+	length int
+}
+
+// NewStreamChart creates a stream chart keeping the last capacity points of each series.
+func NewStreamChart(capacity int) *StreamChart {
+	return &StreamChart{Period: 100 * time.Millisecond, Mode: Braille, capacity: max(capacity, 2)}
+}
+
+// Series adds a series with the name and the color, 0 for the next color of Palette.
+// The first series is the one of Push, it is created without a name if there is none.
+func (s *StreamChart) Series(name string, color int) *StreamChart {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.add(name, color)
+	return s
+}
+
+// add adds a series, s.mu must be held.
+func (s *StreamChart) add(name string, color int) *stream {
+	if color == font.RESET {
+		color = Palette[len(s.series)%len(Palette)]
+	}
+	st := &stream{name: name, color: color, ts: make([]time.Time, s.capacity), vs: make([]float64, s.capacity)}
+	s.series = append(s.series, st)
+	return st
+}
+
+// At binds the chart to the position of the screen, instead of a live region at the cursor.
+func (s *StreamChart) At(x, y int) *StreamChart {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.bindPos, s.posX, s.posY = true, x, y
+	return s
+}
+
+// Push pushes the value v at the time t to the first series, safe for concurrent use.
+func (s *StreamChart) Push(t time.Time, v float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.series) == 0 {
+		s.add("", 0)
+	}
+	s.series[0].push(t, v)
+}
+
+// PushTo pushes the value v at the time t to the series with the name, which is added if not found.
+func (s *StreamChart) PushTo(name string, t time.Time, v float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, st := range s.series {
+		if st.name == name {
+			st.push(t, v)
+			return
+		}
+	}
+	s.add(name, 0).push(t, v)
+}
+
+// push appends a point, overwriting the oldest one when the buffer is full.
+func (st *stream) push(t time.Time, v float64) {
+	i := (st.start + st.length) % len(st.vs)
+	st.ts[i], st.vs[i] = t, v
+	if st.length < len(st.vs) {
+		st.length++
+	} else {
+		st.start = (st.start + 1) % len(st.vs)
+	}
+}
+
+// points returns the points from the oldest, not before since.
+func (st *stream) points(since time.Time) (ts []time.Time, vs []float64) {
+	for k := 0; k < st.length; k++ {
+		i := (st.start + k) % len(st.vs)
+		if st.ts[i].Before(since) {
+			continue
+		}
+		ts, vs = append(ts, st.ts[i]), append(vs, st.vs[i])
+	}
+	return
+}
+
+// Start starts redrawing the chart every period, calling Start on a running chart does nothing.
+func (s *StreamChart) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stop != nil {
+		return
+	}
+	s.stop, s.done = make(chan struct{}), make(chan struct{})
+	go s.run(s.stop, s.done)
+}
+
+// run draws a frame every period until stop is closed, then draws the last frame.
+func (s *StreamChart) run(stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+	period := s.Period
+	if period <= 0 {
+		period = 100 * time.Millisecond
+	}
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+	s.draw()
+	for {
+		select {
+		case <-stop:
+			s.draw()
+			return
+		case <-ticker.C:
+			s.draw()
+		}
+	}
+}
+
+// Stop stops redrawing the chart, the last frame stays on the screen.
+func (s *StreamChart) Stop() {
+	s.mu.Lock()
+	stop, done := s.stop, s.done
+	s.stop = nil
+	s.mu.Unlock()
+	if stop == nil {
+		return
+	}
+	close(stop)
+	<-done
+	s.mu.Lock()
+	region := s.region
+	s.region = nil
+	s.mu.Unlock()
+	if region != nil {
+		region.Close()
+	}
+}
+
+// draw draws a frame, printing lines with utils.ConsoleMutex held like the other widgets.
+func (s *StreamChart) draw() {
+	rows, cols := s.size()
+	lines := s.Lines(rows, cols)
+	for len(lines) < rows {
+		lines = append(lines, "")
+	}
+	s.mu.Lock()
+	bindPos, x, y := s.bindPos, s.posX, s.posY
+	if !bindPos && s.region == nil {
+		s.region = live.New(len(lines))
+	}
+	region := s.region
+	s.mu.Unlock()
+	if bindPos {
+		r := widget.Region{X: x, Y: y, Rows: rows, Cols: cols}
+		for i, line := range lines {
+			r.Line(i, line)
+		}
+		return
+	}
+	region.Set(lines...)
+}
+
+// size returns the size of the chart on the screen.
+func (s *StreamChart) size() (rows, cols int) {
+	rows, cols = s.Rows, s.Cols
+	if rows <= 0 {
+		rows = 12
+	}
+	if cols <= 0 {
+		cols, _ = window.GetConsoleSize()
+		if s.bindPos {
+			cols -= s.posY
+		}
+	}
+	return rows, max(cols, 0)
+}
+
+// Measure implements widget.Widget
+func (s *StreamChart) Measure(c widget.Constraints) widget.Size {
+	rows, cols := s.Rows, s.Cols
+	if rows <= 0 {
+		rows = 12
+	}
+	if cols <= 0 {
+		cols = c.MaxCols
+	}
+	return c.Fit(widget.Size{Rows: rows, Cols: cols})
+}
+
+// Render implements widget.Widget, drawing the current frame.
+func (s *StreamChart) Render(r widget.Region) {
+	for i, line := range s.Lines(r.Rows, r.Cols) {
+		r.Line(i, line)
+	}
+}
+
+// Lines returns the current frame of rows x cols cells as lines of text.
+// The x axis is the time in seconds relative to now, the y axis is scaled to the values shown.
+func (s *StreamChart) Lines(rows, cols int) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	since := time.Time{}
+	if s.Window > 0 {
+		since = now.Add(-s.Window)
+	}
+	p := NewPlot()
+	p.Mode = s.Mode
+	oldest := now
+	var stats []string
+	for _, st := range s.series {
+		ts, vs := st.points(since)
+		xs := make([]float64, len(ts))
+		for i, t := range ts {
+			xs[i] = t.Sub(now).Seconds()
+			oldest = minTime(oldest, t)
+		}
+		p.Add(Series{Name: st.name, Color: st.color, X: xs, Y: vs})
+		if s.ShowStats {
+			stats = append(stats, summary(st, vs))
+		}
+	}
+	if s.Window > 0 {
+		p.Domain(-s.Window.Seconds(), 0)
+	} else if oldest.Before(now) {
+		p.Domain(oldest.Sub(now).Seconds(), 0)
+	}
+	if len(stats) >= rows {
+		stats = nil
+	}
+	lines := p.Lines(rows-len(stats), cols)
+	for _, line := range stats {
+		lines = append(lines, font.Truncate(line, cols))
+	}
+	return lines
+}
+
+// summary returns the min, max and average of the values of the series.
+func summary(st *stream, vs []float64) string {
+	name := st.name
+	if name == "" {
+		name = "value"
+	}
+	if len(vs) == 0 {
+		return font.Decorate(name, st.color) + " no data"
+	}
+	lo, hi, sum := math.Inf(1), math.Inf(-1), 0.0
+	for _, v := range vs {
+		lo, hi, sum = min(lo, v), max(hi, v), sum+v
+	}
+	return fmt.Sprintf("%s min %s  max %s  avg %s", font.Decorate(name, st.color),
+		formatValue(lo), formatValue(hi), formatValue(sum/float64(len(vs))))
+}
+
+// minTime returns the earlier time.
+func minTime(a, b time.Time) time.Time {
+	if b.Before(a) {
+		return b
+	}
+	return a
+}
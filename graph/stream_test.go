@@ -0,0 +1,80 @@
+package graph
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/gngtwhh/gocui/font"
+	"github.com/gngtwhh/gocui/widget"
+)
+
+func TestStreamRing(t *testing.T) {
+	t0 := time.Now()
+	tests := []struct {
+		name     string
+		capacity int
+		values   []float64
+		since    time.Duration
+		want     []float64
+	}{
+		{"empty", 3, nil, 0, nil},
+		{"not full", 3, []float64{1, 2}, 0, []float64{1, 2}},
+		{"full", 3, []float64{1, 2, 3}, 0, []float64{1, 2, 3}},
+		{"oldest overwritten", 3, []float64{1, 2, 3, 4, 5}, 0, []float64{3, 4, 5}},
+		{"minimum capacity", 0, []float64{1, 2, 3}, 0, []float64{2, 3}},
+		{"since", 5, []float64{1, 2, 3, 4}, 2 * time.Second, []float64{3, 4}},
+	}
+	for _, tt := range tests {
+		s := NewStreamChart(tt.capacity)
+		for i, v := range tt.values {
+			s.Push(t0.Add(time.Duration(i)*time.Second), v)
+		}
+		var got []float64
+		if len(s.series) > 0 {
+			_, got = s.series[0].points(t0.Add(tt.since))
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%s: points = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestStreamLines(t *testing.T) {
+	now := time.Now()
+	s := NewStreamChart(2)
+	s.ShowStats = true
+	s.Series("cpu", font.Red)
+	for i, v := range []float64{1, 2, 3} {
+		s.PushTo("cpu", now.Add(time.Duration(i-3)*time.Second), v)
+	}
+	s.PushTo("mem", now, 7)
+	lines := s.Lines(8, 40)
+	if len(lines) != 8 {
+		t.Fatalf("%d lines, want 8", len(lines))
+	}
+	stats := []string{font.Strip(lines[6]), font.Strip(lines[7])}
+	want := []string{"cpu min 2  max 3  avg 2.5", "mem min 7  max 7  avg 7"}
+	if !slices.Equal(stats, want) {
+		t.Errorf("stats = %q, want %q", stats, want)
+	}
+	if len(s.series) != 2 || s.series[1].color != Palette[1] {
+		t.Errorf("the series pushed by name is not added with the next color")
+	}
+	if got := s.Measure(widget.Constraints{MaxCols: 30}); got != (widget.Size{Rows: 12, Cols: 30}) {
+		t.Errorf("Measure = %+v, want 12 x 30", got)
+	}
+}
+
+func TestStreamStats(t *testing.T) {
+	s := NewStreamChart(4)
+	s.ShowStats = true
+	s.Series("", font.Green)
+	lines := s.Lines(3, 40)
+	if got := font.Strip(lines[len(lines)-1]); got != "value no data" {
+		t.Errorf("stats = %q, want %q", got, "value no data")
+	}
+	if lines := s.Lines(1, 40); len(lines) > 1 {
+		t.Errorf("%d lines for a single row", len(lines))
+	}
+}
@@ -0,0 +1,23 @@
+//go:build !linux && !darwin && !freebsd && !netbsd && !openbsd && !dragonfly && !windows
+
+package term
+
+import "os"
+
+// Reader reads a terminal, a pending read cannot be canceled on this platform.
+type Reader struct {
+	f *os.File
+}
+
+// NewReader creates a reader of the terminal f.
+func NewReader(f *os.File) (*Reader, error) {
+	return &Reader{f: f}, nil
+}
+
+// Read reads the input.
+func (r *Reader) Read(p []byte) (int, error) {
+	return r.f.Read(p)
+}
+
+// Cancel does nothing on this platform.
+func (r *Reader) Cancel() {}
@@ -0,0 +1,52 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+
+package term
+
+import (
+	"errors"
+	"io"
+	"os"
+	"sync"
+	"syscall"
+)
+
+// Reader reads a terminal, a pending read can be canceled.
+// The file is duplicated in non-blocking mode and read through the poller of the runtime,
+// so that Cancel wakes up the read instead of leaving it blocked until the next input.
+type Reader struct {
+	fd   int      // the file read, set back to blocking mode by Cancel
+	f    *os.File // the duplicate in non-blocking mode
+	once sync.Once
+}
+
+// NewReader creates a reader of the terminal f.
+func NewReader(f *os.File) (*Reader, error) {
+	fd := int(f.Fd())
+	dup, err := syscall.Dup(fd)
+	if err != nil {
+		return nil, err
+	}
+	if err := syscall.SetNonblock(dup, true); err != nil {
+		_ = syscall.Close(dup)
+		return nil, err
+	}
+	return &Reader{fd: fd, f: os.NewFile(uintptr(dup), f.Name())}, nil
+}
+
+// Read reads the input, it returns io.EOF once the reader is canceled.
+func (r *Reader) Read(p []byte) (int, error) {
+	n, err := r.f.Read(p)
+	if errors.Is(err, os.ErrClosed) {
+		return n, io.EOF
+	}
+	return n, err
+}
+
+// Cancel wakes up the pending read and stops the reader, the input not read yet is left to the next reader.
+func (r *Reader) Cancel() {
+	r.once.Do(func() {
+		_ = r.f.Close()
+		// the duplicate shares the mode of the file, other readers of the file expect blocking reads
+		_ = syscall.SetNonblock(r.fd, false)
+	})
+}
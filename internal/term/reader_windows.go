@@ -0,0 +1,48 @@
+//go:build windows
+
+package term
+
+import (
+	"io"
+	"os"
+	"sync"
+	"syscall"
+)
+
+// Reader reads a terminal, a pending read can be canceled.
+// A read waits for the console input to be signaled before reading it, checking for Cancel meanwhile,
+// so that the read is not left blocked until the next input.
+type Reader struct {
+	f    *os.File
+	done chan struct{}
+	once sync.Once
+}
+
+// NewReader creates a reader of the console f.
+func NewReader(f *os.File) (*Reader, error) {
+	return &Reader{f: f, done: make(chan struct{})}, nil
+}
+
+// Read reads the input, it returns io.EOF once the reader is canceled.
+func (r *Reader) Read(p []byte) (int, error) {
+	h := syscall.Handle(r.f.Fd())
+	for {
+		select {
+		case <-r.done:
+			return 0, io.EOF
+		default:
+		}
+		ev, err := syscall.WaitForSingleObject(h, 100)
+		if err != nil {
+			return 0, err
+		}
+		if ev == syscall.WAIT_OBJECT_0 {
+			return r.f.Read(p)
+		}
+	}
+}
+
+// Cancel stops the reader, a pending read returns within 100ms, the input not read yet is left to the next reader.
+func (r *Reader) Cancel() {
+	r.once.Do(func() { close(r.done) })
+}
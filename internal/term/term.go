@@ -0,0 +1,10 @@
+// Package term switches the terminal between cooked and raw mode.
+package term
+
+import "errors"
+
+// ErrUnsupported is returned on platforms where raw mode is not implemented.
+var ErrUnsupported = errors.New("term: raw mode is not supported on this platform")
+
+// ErrTimeout is returned by Query when the terminal does not reply.
+var ErrTimeout = errors.New("term: no reply from the terminal")
@@ -0,0 +1,28 @@
+//go:build !linux && !darwin && !freebsd && !netbsd && !openbsd && !dragonfly && !windows
+
+package term
+
+import "os"
+
+// State is the terminal state to restore.
+type State struct{}
+
+// IsTerminal reports whether fd is a terminal.
+func IsTerminal(fd int) bool {
+	return false
+}
+
+// MakeRaw is not supported on this platform.
+func MakeRaw(fd int) (*State, error) {
+	return nil, ErrUnsupported
+}
+
+// Query is not supported on this platform.
+func Query(in, out *os.File, request string, end byte) ([]byte, error) {
+	return nil, ErrUnsupported
+}
+
+// Restore does nothing on this platform.
+func Restore(fd int, s *State) error {
+	return nil
+}
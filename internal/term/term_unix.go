@@ -0,0 +1,105 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+
+package term
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+// State is the terminal state to restore.
+type State struct {
+	termios syscall.Termios
+}
+
+// IsTerminal reports whether fd is a terminal.
+func IsTerminal(fd int) bool {
+	_, err := getTermios(fd)
+	return err == nil
+}
+
+// MakeRaw puts the terminal into raw mode: no echo, no line buffering and no signal keys,
+// output processing is kept so that "\n" still moves to the beginning of the next line.
+// It returns the previous state to be restored.
+func MakeRaw(fd int) (*State, error) {
+	old, err := getTermios(fd)
+	if err != nil {
+		return nil, err
+	}
+	raw := *old
+	raw.Iflag &^= syscall.IGNBRK | syscall.BRKINT | syscall.PARMRK | syscall.ISTRIP |
+		syscall.INLCR | syscall.IGNCR | syscall.ICRNL | syscall.IXON
+	raw.Lflag &^= syscall.ECHO | syscall.ECHONL | syscall.ICANON | syscall.ISIG | syscall.IEXTEN
+	raw.Cflag &^= syscall.CSIZE | syscall.PARENB
+	raw.Cflag |= syscall.CS8
+	raw.Cc[syscall.VMIN] = 1
+	raw.Cc[syscall.VTIME] = 0
+	if err := setTermios(fd, &raw); err != nil {
+		return nil, err
+	}
+	return &State{termios: *old}, nil
+}
+
+// Query writes the request to out and reads the reply of the terminal from in until the byte end.
+// Echo and line buffering are turned off meanwhile, it fails if the terminal does not reply within a second.
+func Query(in, out *os.File, request string, end byte) ([]byte, error) {
+	fd := int(in.Fd())
+	old, err := getTermios(fd)
+	if err != nil {
+		return nil, err
+	}
+	t := *old
+	t.Lflag &^= syscall.ECHO | syscall.ICANON
+	t.Cc[syscall.VMIN] = 0
+	t.Cc[syscall.VTIME] = 1 // reads return after 100ms without input
+	if err := setTermios(fd, &t); err != nil {
+		return nil, err
+	}
+	defer setTermios(fd, old)
+
+	if _, err := out.WriteString(request); err != nil {
+		return nil, err
+	}
+	var reply []byte
+	buf := make([]byte, 32)
+	for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); {
+		n, err := in.Read(buf)
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		reply = append(reply, buf[:n]...)
+		if i := bytes.IndexByte(reply, end); i >= 0 {
+			return reply[:i+1], nil
+		}
+	}
+	return nil, ErrTimeout
+}
+
+// Restore restores the terminal to the given state.
+func Restore(fd int, s *State) error {
+	if s == nil {
+		return nil
+	}
+	return setTermios(fd, &s.termios)
+}
+
+func getTermios(fd int) (*syscall.Termios, error) {
+	var t syscall.Termios
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL,
+		uintptr(fd), uintptr(ioctlGetTermios), uintptr(unsafe.Pointer(&t))); errno != 0 {
+		return nil, errno
+	}
+	return &t, nil
+}
+
+func setTermios(fd int, t *syscall.Termios) error {
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL,
+		uintptr(fd), uintptr(ioctlSetTermios), uintptr(unsafe.Pointer(t))); errno != 0 {
+		return errno
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+//go:build windows
+
+package term
+
+import (
+	"bytes"
+	"os"
+	"syscall"
+	"time"
+)
+
+const (
+	enableProcessedInput       = 0x0001
+	enableLineInput            = 0x0002
+	enableEchoInput            = 0x0004
+	enableVirtualTerminalInput = 0x0200
+)
+
+var (
+	kernel32DLL        = syscall.NewLazyDLL("kernel32.dll")
+	setConsoleModeProc = kernel32DLL.NewProc("SetConsoleMode")
+)
+
+// State is the terminal state to restore.
+type State struct {
+	mode uint32
+}
+
+// IsTerminal reports whether fd is a terminal.
+func IsTerminal(fd int) bool {
+	var mode uint32
+	return syscall.GetConsoleMode(syscall.Handle(fd), &mode) == nil
+}
+
+// MakeRaw puts the console into raw mode: no echo, no line buffering and no signal keys,
+// keys are reported as virtual terminal sequences.
+// It returns the previous state to be restored.
+func MakeRaw(fd int) (*State, error) {
+	return setMode(fd, enableEchoInput|enableLineInput|enableProcessedInput, enableVirtualTerminalInput)
+}
+
+// Query writes the request to out and reads the reply of the terminal from in until the byte end.
+// Echo and line buffering are turned off meanwhile, it fails if the terminal does not reply within a second.
+// If it fails, the pending read consumes the next input.
+func Query(in, out *os.File, request string, end byte) ([]byte, error) {
+	fd := int(in.Fd())
+	state, err := setMode(fd, enableEchoInput|enableLineInput, enableVirtualTerminalInput)
+	if err != nil {
+		return nil, err
+	}
+	defer Restore(fd, state)
+
+	if _, err := out.WriteString(request); err != nil {
+		return nil, err
+	}
+	ch := make(chan []byte, 1)
+	go func() {
+		var reply []byte
+		buf := make([]byte, 32)
+		for {
+			n, err := in.Read(buf)
+			if err != nil {
+				ch <- nil
+				return
+			}
+			reply = append(reply, buf[:n]...)
+			if i := bytes.IndexByte(reply, end); i >= 0 {
+				ch <- reply[:i+1]
+				return
+			}
+		}
+	}()
+	select {
+	case reply := <-ch:
+		if reply == nil {
+			return nil, ErrTimeout
+		}
+		return reply, nil
+	case <-time.After(time.Second):
+		return nil, ErrTimeout
+	}
+}
+
+// Restore restores the console to the given state.
+func Restore(fd int, s *State) error {
+	if s == nil {
+		return nil
+	}
+	return setConsoleMode(fd, s.mode)
+}
+
+func setMode(fd int, clear, set uint32) (*State, error) {
+	var mode uint32
+	if err := syscall.GetConsoleMode(syscall.Handle(fd), &mode); err != nil {
+		return nil, err
+	}
+	if err := setConsoleMode(fd, mode&^clear|set); err != nil {
+		return nil, err
+	}
+	return &State{mode: mode}, nil
+}
+
+func setConsoleMode(fd int, mode uint32) error {
+	if r, _, err := setConsoleModeProc.Call(uintptr(fd), uintptr(mode)); r == 0 {
+		return err
+	}
+	return nil
+}
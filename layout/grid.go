@@ -0,0 +1,121 @@
+package layout
+
+import "github.com/gngtwhh/gocui/widget"
+
+// Cell is a child of a grid, placed at (Row, Col) and spanning RowSpan x ColSpan tracks.
+type Cell struct {
+	Widget           widget.Widget
+	Row, Col         int
+	RowSpan, ColSpan int
+}
+
+// Grid lays out its children in rows and columns.
+// Gap is used both between rows and between columns.
+type Grid struct {
+	Property
+	Rows, Cols []Sizing // sizing of each row and each column
+	Cells      []Cell
+}
+
+// NewGrid creates a grid with the given sizing of rows and columns.
+func NewGrid(rows, cols []Sizing, mfs ...ModFunc) *Grid {
+	return &Grid{Property: newProperty(mfs), Rows: rows, Cols: cols}
+}
+
+// Place puts a widget at (row, col) of the grid.
+func (g *Grid) Place(w widget.Widget, row, col int) *Grid {
+	return g.PlaceSpan(w, row, col, 1, 1)
+}
+
+// PlaceSpan puts a widget at (row, col) of the grid, spanning rowSpan rows and colSpan columns.
+func (g *Grid) PlaceSpan(w widget.Widget, row, col, rowSpan, colSpan int) *Grid {
+	g.Cells = append(g.Cells, Cell{Widget: w, Row: row, Col: col, RowSpan: max(rowSpan, 1), ColSpan: max(colSpan, 1)})
+	return g
+}
+
+// Measure implements widget.Widget
+func (g *Grid) Measure(c widget.Constraints) widget.Size {
+	rows, cols := g.natural(shrink(c, g.Margin))
+	size := widget.Size{Rows: g.Top + g.Bottom, Cols: g.Left + g.Right}
+	size.Rows += sum(rows) + g.Gap*max(len(rows)-1, 0)
+	size.Cols += sum(cols) + g.Gap*max(len(cols)-1, 0)
+	return c.Fit(size)
+}
+
+// Render implements widget.Widget
+func (g *Grid) Render(r widget.Region) {
+	for i, sub := range g.Layout(r) {
+		g.Cells[i].Widget.Render(sub)
+	}
+}
+
+// Layout computes the region of each cell inside r.
+func (g *Grid) Layout(r widget.Region) []widget.Region {
+	inner := r.Sub(g.Top, g.Left, r.Rows-g.Top-g.Bottom, r.Cols-g.Left-g.Right)
+	naturalRows, naturalCols := g.natural(inner.Constraints())
+	heights := distribute(inner.Rows, g.Gap, g.Rows, naturalRows)
+	widths := distribute(inner.Cols, g.Gap, g.Cols, naturalCols)
+
+	regions := make([]widget.Region, len(g.Cells))
+	for i, cell := range g.Cells {
+		x, rows := track(heights, g.Gap, cell.Row, cell.RowSpan)
+		y, cols := track(widths, g.Gap, cell.Col, cell.ColSpan)
+		m := cell.Widget.Measure(widget.Constraints{MaxRows: rows, MaxCols: cols})
+		offX, h := align(g.Align, m.Rows, rows)
+		offY, w := align(g.Align, m.Cols, cols)
+		regions[i] = inner.Sub(x+offX, y+offY, h, w)
+	}
+	return regions
+}
+
+// natural returns the measured size of each row and column, cells spanning several tracks are ignored.
+func (g *Grid) natural(c widget.Constraints) (rows, cols []int) {
+	rows, cols = make([]int, len(g.Rows)), make([]int, len(g.Cols))
+	for _, cell := range g.Cells {
+		if cell.Row < 0 || cell.Row >= len(rows) || cell.Col < 0 || cell.Col >= len(cols) {
+			continue
+		}
+		m := cell.Widget.Measure(c)
+		if cell.RowSpan == 1 {
+			rows[cell.Row] = max(rows[cell.Row], m.Rows)
+		}
+		if cell.ColSpan == 1 {
+			cols[cell.Col] = max(cols[cell.Col], m.Cols)
+		}
+	}
+	for i, s := range g.Rows {
+		if s.Kind == FIXED {
+			rows[i] = int(s.Value)
+		}
+	}
+	for i, s := range g.Cols {
+		if s.Kind == FIXED {
+			cols[i] = int(s.Value)
+		}
+	}
+	return rows, cols
+}
+
+// track returns the offset and the length of span tracks starting from index i.
+func track(lengths []int, gap, i, span int) (offset, length int) {
+	if i < 0 || i >= len(lengths) {
+		return 0, 0
+	}
+	for j := 0; j < i; j++ {
+		offset += lengths[j] + gap
+	}
+	for j := i; j < i+span && j < len(lengths); j++ {
+		if j > i {
+			length += gap
+		}
+		length += lengths[j]
+	}
+	return offset, length
+}
+
+func sum(a []int) (s int) {
+	for _, v := range a {
+		s += v
+	}
+	return s
+}
@@ -0,0 +1,43 @@
+package layout
+
+// ModFunc is a function that modifies the Property of a layout.
+type ModFunc func(p *Property)
+
+// Property is the property shared by all the layouts.
+type Property struct {
+	Margin     // space kept around the layout
+	Gap    int // cells between two children
+	Align  int // alignment of the children on the cross axis, default Stretch
+}
+
+// WithGap sets the cells between two children.
+func WithGap(gap int) ModFunc {
+	return func(p *Property) {
+		p.Gap = max(gap, 0)
+	}
+}
+
+// WithMargin sets the space kept around the layout.
+func WithMargin(top, right, bottom, left int) ModFunc {
+	return func(p *Property) {
+		p.Margin = Margin{Top: top, Right: right, Bottom: bottom, Left: left}
+	}
+}
+
+// WithAlign sets the alignment of the children on the cross axis: Stretch, Start, Center or End.
+func WithAlign(a int) ModFunc {
+	return func(p *Property) {
+		p.Align = a
+	}
+}
+
+// newProperty applies the modify functions to an empty property.
+func newProperty(mfs []ModFunc) Property {
+	var p Property
+	for _, mf := range mfs {
+		if mf != nil {
+			mf(&p)
+		}
+	}
+	return p
+}
@@ -0,0 +1,23 @@
+package layout
+
+import (
+	"github.com/gngtwhh/gocui/widget"
+	"github.com/gngtwhh/gocui/window"
+)
+
+// Fill renders the widget over the whole terminal.
+func Fill(w widget.Widget) {
+	width, height := window.GetConsoleSize()
+	w.Render(widget.Region{Rows: height, Cols: width})
+}
+
+// Watch renders the widget over the whole terminal, and renders it again each time the terminal is resized,
+// so the regions of the children are recomputed from the new size. Call stop to stop watching.
+func Watch(w widget.Widget) (stop func()) {
+	window.ClearScreen()
+	Fill(w)
+	return window.OnResize(func(width, height int) {
+		window.ClearScreen()
+		w.Render(widget.Region{Rows: height, Cols: width})
+	})
+}
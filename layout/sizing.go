@@ -0,0 +1,108 @@
+package layout
+
+// Kinds of sizing
+const (
+	AUTO = iota
+	FIXED
+	PERCENT
+	FLEX
+)
+
+// Alignment of a child on the cross axis
+const (
+	Stretch = iota
+	Start
+	Center
+	End
+)
+
+// Sizing is how much space a child takes on the main axis of a stack, or a track of a grid takes.
+type Sizing struct {
+	Kind  int     // AUTO, FIXED, PERCENT or FLEX
+	Value float64 // cells for FIXED, percentage for PERCENT, weight for FLEX
+}
+
+// Margin is the space kept empty around a layout.
+type Margin struct {
+	Top, Right, Bottom, Left int
+}
+
+// Auto sizes the child to the size it measures.
+func Auto() Sizing {
+	return Sizing{Kind: AUTO}
+}
+
+// Fixed sizes the child to n cells.
+func Fixed(n int) Sizing {
+	return Sizing{Kind: FIXED, Value: float64(n)}
+}
+
+// Percent sizes the child to p percent of the available space.
+func Percent(p float64) Sizing {
+	return Sizing{Kind: PERCENT, Value: p}
+}
+
+// Flex shares the space left by the other children among the flex children, in proportion to their weight.
+func Flex(weight float64) Sizing {
+	return Sizing{Kind: FLEX, Value: weight}
+}
+
+// distribute splits total cells among the sizings, with gap cells between two of them.
+// natural is the measured size of each child, used by AUTO sizings.
+func distribute(total, gap int, sizes []Sizing, natural []int) []int {
+	n := len(sizes)
+	res := make([]int, n)
+	if n == 0 {
+		return res
+	}
+	avail := max(total-gap*(n-1), 0)
+	left := avail
+	var weights float64
+	for i, s := range sizes {
+		switch s.Kind {
+		case FIXED:
+			res[i] = int(s.Value)
+		case PERCENT:
+			res[i] = int(float64(avail) * s.Value / 100)
+		case FLEX:
+			weights += max(s.Value, 0)
+			continue
+		default:
+			res[i] = natural[i]
+		}
+		res[i] = max(min(res[i], left), 0) // children that do not fit are clipped
+		left -= res[i]
+	}
+	if weights <= 0 || left <= 0 {
+		return res
+	}
+	// share the space left among flex children, the remainder goes to the first ones
+	shared := 0
+	for i, s := range sizes {
+		if s.Kind == FLEX {
+			res[i] = int(float64(left) * max(s.Value, 0) / weights)
+			shared += res[i]
+		}
+	}
+	for i := 0; shared < left; i = (i + 1) % n {
+		if sizes[i].Kind == FLEX && sizes[i].Value > 0 {
+			res[i]++
+			shared++
+		}
+	}
+	return res
+}
+
+// align returns the offset and the size of a child of natural size size in space cells.
+func align(a, size, space int) (offset, length int) {
+	size = min(size, space)
+	switch a {
+	case Start:
+		return 0, size
+	case Center:
+		return (space - size) / 2, size
+	case End:
+		return space - size, size
+	}
+	return 0, space
+}
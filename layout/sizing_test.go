@@ -0,0 +1,52 @@
+package layout
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestDistribute(t *testing.T) {
+	tests := []struct {
+		name    string
+		total   int
+		gap     int
+		sizes   []Sizing
+		natural []int
+		want    []int
+	}{
+		{"empty", 10, 1, nil, nil, []int{}},
+		{"fixed and auto", 20, 0, []Sizing{Fixed(5), Auto()}, []int{0, 7}, []int{5, 7}},
+		{"percent of the space without gaps", 22, 2, []Sizing{Percent(50), Percent(50)}, []int{0, 0}, []int{10, 10}},
+		{"flex shares the rest", 20, 0, []Sizing{Fixed(8), Flex(1), Flex(2)}, []int{0, 0, 0}, []int{8, 4, 8}},
+		{"flex remainder to the first", 10, 0, []Sizing{Flex(1), Flex(1), Flex(1)}, []int{0, 0, 0}, []int{4, 3, 3}},
+		{"gaps", 10, 1, []Sizing{Flex(1), Flex(1)}, []int{0, 0}, []int{5, 4}},
+		{"clipped when too large", 10, 0, []Sizing{Fixed(6), Fixed(6), Flex(1)}, []int{0, 0, 0}, []int{6, 4, 0}},
+		{"zero weight", 10, 0, []Sizing{Flex(0), Flex(1)}, []int{0, 0}, []int{0, 10}},
+		{"gaps larger than the total", 2, 5, []Sizing{Auto(), Auto()}, []int{3, 3}, []int{0, 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := distribute(tt.total, tt.gap, tt.sizes, tt.natural); !slices.Equal(got, tt.want) {
+				t.Errorf("distribute(%d, %d) = %v, want %v", tt.total, tt.gap, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAlign(t *testing.T) {
+	tests := []struct {
+		a, size, space int
+		offset, length int
+	}{
+		{Stretch, 3, 10, 0, 10},
+		{Start, 3, 10, 0, 3},
+		{Center, 3, 10, 3, 3},
+		{End, 3, 10, 7, 3},
+		{End, 12, 10, 0, 10},
+	}
+	for _, tt := range tests {
+		if offset, length := align(tt.a, tt.size, tt.space); offset != tt.offset || length != tt.length {
+			t.Errorf("align(%d, %d, %d) = %d, %d, want %d, %d", tt.a, tt.size, tt.space, offset, length, tt.offset, tt.length)
+		}
+	}
+}
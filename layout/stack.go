@@ -0,0 +1,154 @@
+package layout
+
+import "github.com/gngtwhh/gocui/widget"
+
+// Directions of a stack
+const (
+	Vertical = iota
+	Horizontal
+)
+
+// Item is a child of a stack.
+type Item struct {
+	Widget widget.Widget
+	Size   Sizing // size on the main axis
+	Align  int    // alignment on the cross axis, used when AlignSet is true, otherwise the stack's Align is used
+	// AlignSet indicates that Align overrides the alignment of the stack
+	AlignSet bool
+}
+
+// Stack lays out its children one after another, vertically or horizontally.
+// A Stack is itself a widget.Widget, so stacks can be nested.
+type Stack struct {
+	Property
+	Direction int
+	Items     []Item
+}
+
+// Column creates a stack that lays out its children from top to bottom.
+func Column(mfs ...ModFunc) *Stack {
+	return &Stack{Property: newProperty(mfs), Direction: Vertical}
+}
+
+// Row creates a stack that lays out its children from left to right.
+func Row(mfs ...ModFunc) *Stack {
+	return &Stack{Property: newProperty(mfs), Direction: Horizontal}
+}
+
+// HSplit splits the space horizontally, the left widget takes ratio (0~1) of the width.
+func HSplit(left, right widget.Widget, ratio float64, mfs ...ModFunc) *Stack {
+	return Row(mfs...).Add(left, Percent(ratio*100)).Add(right, Flex(1))
+}
+
+// VSplit splits the space vertically, the top widget takes ratio (0~1) of the height.
+func VSplit(top, bottom widget.Widget, ratio float64, mfs ...ModFunc) *Stack {
+	return Column(mfs...).Add(top, Percent(ratio*100)).Add(bottom, Flex(1))
+}
+
+// Add appends a child with the given size on the main axis.
+func (s *Stack) Add(w widget.Widget, size Sizing) *Stack {
+	s.Items = append(s.Items, Item{Widget: w, Size: size})
+	return s
+}
+
+// AddAligned appends a child with the given size, aligned on the cross axis regardless of the stack's Align.
+func (s *Stack) AddAligned(w widget.Widget, size Sizing, align int) *Stack {
+	s.Items = append(s.Items, Item{Widget: w, Size: size, Align: align, AlignSet: true})
+	return s
+}
+
+// Measure implements widget.Widget
+func (s *Stack) Measure(c widget.Constraints) widget.Size {
+	inner := shrink(c, s.Margin)
+	var main, cross int
+	for i, item := range s.Items {
+		if i > 0 {
+			main += s.Gap
+		}
+		m := item.Widget.Measure(inner)
+		if item.Size.Kind == FIXED {
+			m = s.resize(m, int(item.Size.Value))
+		}
+		if s.Direction == Vertical {
+			main += m.Rows
+			cross = max(cross, m.Cols)
+		} else {
+			main += m.Cols
+			cross = max(cross, m.Rows)
+		}
+	}
+	size := widget.Size{Rows: main, Cols: cross}
+	if s.Direction == Horizontal {
+		size = widget.Size{Rows: cross, Cols: main}
+	}
+	size.Rows += s.Top + s.Bottom
+	size.Cols += s.Left + s.Right
+	return c.Fit(size)
+}
+
+// Render implements widget.Widget
+func (s *Stack) Render(r widget.Region) {
+	for i, sub := range s.Layout(r) {
+		s.Items[i].Widget.Render(sub)
+	}
+}
+
+// Layout computes the region of each child inside r.
+func (s *Stack) Layout(r widget.Region) []widget.Region {
+	inner := r.Sub(s.Top, s.Left, r.Rows-s.Top-s.Bottom, r.Cols-s.Left-s.Right)
+	total, space := inner.Rows, inner.Cols
+	if s.Direction == Horizontal {
+		total, space = inner.Cols, inner.Rows
+	}
+	sizes := make([]Sizing, len(s.Items))
+	natural := make([]int, len(s.Items))
+	measured := make([]widget.Size, len(s.Items))
+	for i, item := range s.Items {
+		sizes[i] = item.Size
+		measured[i] = item.Widget.Measure(inner.Constraints())
+		natural[i] = measured[i].Rows
+		if s.Direction == Horizontal {
+			natural[i] = measured[i].Cols
+		}
+	}
+	lengths := distribute(total, s.Gap, sizes, natural)
+
+	regions := make([]widget.Region, len(s.Items))
+	pos := 0
+	for i, item := range s.Items {
+		a := s.Align
+		if item.AlignSet {
+			a = item.Align
+		}
+		if s.Direction == Vertical {
+			off, w := align(a, measured[i].Cols, space)
+			regions[i] = inner.Sub(pos, off, lengths[i], w)
+		} else {
+			off, h := align(a, measured[i].Rows, space)
+			regions[i] = inner.Sub(off, pos, h, lengths[i])
+		}
+		pos += lengths[i] + s.Gap
+	}
+	return regions
+}
+
+// resize sets the main axis of the size to n.
+func (s *Stack) resize(size widget.Size, n int) widget.Size {
+	if s.Direction == Vertical {
+		size.Rows = n
+	} else {
+		size.Cols = n
+	}
+	return size
+}
+
+// shrink removes the margin from the constraints.
+func shrink(c widget.Constraints, m Margin) widget.Constraints {
+	if c.MaxRows > 0 {
+		c.MaxRows = max(c.MaxRows-m.Top-m.Bottom, 1)
+	}
+	if c.MaxCols > 0 {
+		c.MaxCols = max(c.MaxCols-m.Left-m.Right, 1)
+	}
+	return c
+}
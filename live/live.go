@@ -0,0 +1,182 @@
+// Package live manages regions of lines reserved below the cursor and redrawn in place,
+// relative to the cursor instead of absolute rows, so they keep working when the terminal scrolls.
+// All the active regions form a block at the bottom of the output, like the live area of modern CLI tools.
+package live
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/gngtwhh/gocui/cursor"
+	"github.com/gngtwhh/gocui/font"
+	"github.com/gngtwhh/gocui/utils"
+	"github.com/gngtwhh/gocui/widget"
+	"github.com/gngtwhh/gocui/window"
+)
+
+// Region is a block of lines redrawn in place.
+type Region struct {
+	lines  []string
+	closed bool
+}
+
+var (
+	mu      sync.Mutex
+	regions []*Region // the active regions, from top to bottom
+	drawn   int       // the number of lines of the block on the screen, the cursor is on the last one
+	pending []string  // lines to print above the block on the next redraw
+
+	consoleSize = window.GetConsoleSize // the size of the terminal, replaced by the tests
+)
+
+// New reserves height blank lines starting from the line of the cursor, scrolling the terminal if needed.
+// The content of the cursor line is replaced, print a newline before to keep it.
+func New(height int) *Region {
+	r := &Region{lines: make([]string, max(height, 1))}
+	mu.Lock()
+	regions = append(regions, r)
+	redraw()
+	mu.Unlock()
+	return r
+}
+
+// Set replaces the lines of the region and redraws it, the region grows or shrinks to the number of lines.
+func (r *Region) Set(lines ...string) {
+	mu.Lock()
+	defer mu.Unlock()
+	if r.closed {
+		return
+	}
+	r.lines = append(r.lines[:0], lines...)
+	redraw()
+}
+
+// SetLine replaces the i-th line of the region and redraws it, the region grows if needed.
+func (r *Region) SetLine(i int, line string) {
+	mu.Lock()
+	defer mu.Unlock()
+	if r.closed || i < 0 {
+		return
+	}
+	for len(r.lines) <= i {
+		r.lines = append(r.lines, "")
+	}
+	r.lines[i] = line
+	redraw()
+}
+
+// Lines returns a copy of the lines of the region.
+func (r *Region) Lines() []string {
+	mu.Lock()
+	defer mu.Unlock()
+	return append([]string(nil), r.lines...)
+}
+
+// Render renders the widget into the region, sized to the width of the terminal.
+// Widgets that update themselves, like a running progress bar, keep updating the region until it is closed.
+func (r *Region) Render(w widget.Widget) {
+	width, _ := consoleSize()
+	s := w.Measure(widget.Constraints{MaxCols: width})
+	buf := widget.NewBuffer(s.Rows, s.Cols)
+	w.Render(buf.Region())
+	buf.OnChange = func() {
+		r.Set(buf.Lines()...)
+	}
+	r.Set(buf.Lines()...)
+}
+
+// Close stops redrawing the region, its lines stay on the screen as normal output.
+// If other regions are still active, the lines are moved above them,
+// otherwise the cursor moves to the line below them for the next output.
+func (r *Region) Close() {
+	mu.Lock()
+	defer mu.Unlock()
+	if r.closed {
+		return
+	}
+	r.closed = true
+	for i, v := range regions {
+		if v == r {
+			regions = append(regions[:i], regions[i+1:]...)
+			break
+		}
+	}
+	if len(regions) == 0 {
+		// leave the lines as they are, the next output starts on the line below them
+		drawn = 0
+		utils.ConsoleMutex.Lock()
+		fmt.Print("\n")
+		utils.ConsoleMutex.Unlock()
+		return
+	}
+	pending = append(pending, r.lines...)
+	redraw()
+}
+
+// Remove stops redrawing the region and erases its lines.
+func (r *Region) Remove() {
+	mu.Lock()
+	defer mu.Unlock()
+	if r.closed {
+		return
+	}
+	r.closed = true
+	for i, v := range regions {
+		if v == r {
+			regions = append(regions[:i], regions[i+1:]...)
+			break
+		}
+	}
+	redraw()
+}
+
+// Active reports whether any region is being redrawn.
+func Active() bool {
+	mu.Lock()
+	defer mu.Unlock()
+	return len(regions) > 0
+}
+
+// redraw prints the pending lines, then all the active regions over the block on the screen.
+// mu must be held.
+func redraw() {
+	width, height := consoleSize()
+	out := strings.Builder{}
+	if drawn > 0 {
+		out.WriteString("\r")
+		if drawn > 1 {
+			out.WriteString(cursor.UpSeq(drawn - 1))
+		}
+	}
+	for _, line := range pending {
+		out.WriteString("\r\033[2K")
+		out.WriteString(line)
+		out.WriteString("\n")
+	}
+	pending = nil
+	var lines []string
+	for _, r := range regions {
+		lines = append(lines, r.lines...)
+	}
+	if height > 0 && len(lines) > height {
+		// the cursor cannot move up above the screen, so the top lines of a block taller than it are not shown
+		lines = lines[len(lines)-height:]
+	}
+	for i, line := range lines {
+		if i > 0 {
+			out.WriteString("\n")
+		}
+		if width > 0 {
+			line = font.Truncate(line, width) // a wrapped line would break the line count
+		}
+		out.WriteString("\r\033[2K")
+		out.WriteString(line)
+	}
+	out.WriteString("\033[J") // erase what is left of a taller block
+	drawn = len(lines)
+
+	utils.ConsoleMutex.Lock()
+	defer utils.ConsoleMutex.Unlock()
+	fmt.Print(out.String())
+}
@@ -0,0 +1,118 @@
+package live
+
+import (
+	"io"
+	"os"
+	"slices"
+	"testing"
+
+	"github.com/gngtwhh/gocui/window"
+)
+
+// capture returns what f writes to the standard output.
+func capture(t *testing.T, f func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+	out := make(chan string)
+	go func() {
+		b, _ := io.ReadAll(r)
+		out <- string(b)
+	}()
+	f()
+	w.Close()
+	return <-out
+}
+
+func TestRegion(t *testing.T) {
+	var a, b *Region
+	tests := []struct {
+		name string
+		f    func()
+		want string
+	}{
+		{"new", func() { a = New(2) }, "\r\033[2K\n\r\033[2K\033[J"},
+		{"set", func() { a.Set("a", "b") }, "\r\033[1A\r\033[2Ka\n\r\033[2Kb\033[J"},
+		{"set line grows", func() { a.SetLine(2, "c") }, "\r\033[1A\r\033[2Ka\n\r\033[2Kb\n\r\033[2Kc\033[J"},
+		{"second region below", func() { b = New(1) },
+			"\r\033[2A\r\033[2Ka\n\r\033[2Kb\n\r\033[2Kc\n\r\033[2K\033[J"},
+		{"shrink", func() { a.Set("x") }, "\r\033[3A\r\033[2Kx\n\r\033[2K\033[J"},
+		{"print above", func() { Print("log") }, "\r\033[1A\r\033[2Klog\n\r\033[2Kx\n\r\033[2K\033[J"},
+		{"close moves the lines above", func() { a.Close() }, "\r\033[1A\r\033[2Kx\n\r\033[2K\033[J"},
+		{"closed region is not redrawn", func() { a.Set("y") }, ""},
+		{"close the last region", func() { b.Close() }, "\n"},
+		{"print without regions", func() { Printf("%d", 1) }, "1\n"},
+	}
+	for _, tt := range tests {
+		if out := capture(t, tt.f); out != tt.want {
+			t.Errorf("%s: printed %q, want %q", tt.name, out, tt.want)
+		}
+	}
+	if Active() {
+		t.Error("active after all the regions are closed")
+	}
+	if got := a.Lines(); !slices.Equal(got, []string{"x"}) {
+		t.Errorf("lines of the closed region = %q, want %q", got, []string{"x"})
+	}
+}
+
+func TestRemove(t *testing.T) {
+	var r *Region
+	capture(t, func() { r = New(1); r.Set("a", "b") })
+	if out := capture(t, r.Remove); out != "\r\033[1A\033[J" {
+		t.Errorf("Remove printed %q, want the lines erased", out)
+	}
+	if out := capture(t, r.Close); out != "" {
+		t.Errorf("Close after Remove printed %q", out)
+	}
+}
+
+func TestWriter(t *testing.T) {
+	w := Writer()
+	if out := capture(t, func() { w.Write([]byte("direct")) }); out != "direct" {
+		t.Errorf("without regions the writer printed %q, want %q", out, "direct")
+	}
+	var r *Region
+	capture(t, func() { r = New(1); r.Set("bar") })
+	tests := []struct {
+		data string
+		want string
+	}{
+		{"par", ""},
+		{"tial\nnext", "\r\r\033[2Kpartial\n\r\033[2Kbar\033[J"},
+		{"\n", "\r\r\033[2Knext\n\r\033[2Kbar\033[J"},
+	}
+	for _, tt := range tests {
+		if out := capture(t, func() { w.Write([]byte(tt.data)) }); out != tt.want {
+			t.Errorf("Write(%q) printed %q, want %q", tt.data, out, tt.want)
+		}
+	}
+	capture(t, r.Close)
+}
+
+func TestClampToTerminal(t *testing.T) {
+	consoleSize = func() (int, int) { return 4, 2 }
+	defer func() { consoleSize = window.GetConsoleSize }()
+	var r *Region
+	capture(t, func() { r = New(1) })
+	tests := []struct {
+		name  string
+		lines []string
+		want  string
+	}{
+		{"truncated to the width", []string{"abcdef"}, "\r\r\033[2Kabcd\033[J"},
+		{"top lines above the screen", []string{"a", "b", "c"}, "\r\r\033[2Kb\n\r\033[2Kc\033[J"},
+		{"redrawn from the top of the screen", []string{"x", "y", "z"}, "\r\033[1A\r\033[2Ky\n\r\033[2Kz\033[J"},
+	}
+	for _, tt := range tests {
+		if out := capture(t, func() { r.Set(tt.lines...) }); out != tt.want {
+			t.Errorf("%s: printed %q, want %q", tt.name, out, tt.want)
+		}
+	}
+	capture(t, r.Close)
+}
@@ -0,0 +1,76 @@
+package live
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/gngtwhh/gocui/utils"
+)
+
+// Print prints the text above the active regions, which are redrawn below it.
+// Without active regions, the text is printed as is. A missing trailing newline is added.
+func Print(text string) {
+	if !strings.HasSuffix(text, "\n") {
+		text += "\n"
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	printLocked(text)
+}
+
+// Println formats the operands like fmt.Println and prints them above the active regions.
+func Println(a ...any) {
+	Print(fmt.Sprintln(a...))
+}
+
+// Printf formats like fmt.Printf and prints the text above the active regions.
+func Printf(format string, a ...any) {
+	Print(fmt.Sprintf(format, a...))
+}
+
+// printLocked prints the text, which ends with a newline. mu must be held.
+func printLocked(text string) {
+	if len(regions) == 0 && drawn == 0 {
+		utils.ConsoleMutex.Lock()
+		fmt.Print(text)
+		utils.ConsoleMutex.Unlock()
+		return
+	}
+	pending = append(pending, strings.Split(strings.TrimSuffix(text, "\n"), "\n")...)
+	redraw()
+}
+
+// writer is an io.Writer printing complete lines above the active regions.
+type writer struct {
+	mu  sync.Mutex
+	buf []byte // a line not terminated yet
+}
+
+// Writer returns an io.Writer that prints above the active regions, suitable for log.SetOutput
+// or the handlers of log/slog. Lines are printed once complete, while no region is active
+// the data is written directly.
+func Writer() io.Writer {
+	return &writer{}
+}
+
+// Write implements io.Writer
+func (w *writer) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	mu.Lock()
+	defer mu.Unlock()
+	if len(regions) == 0 && drawn == 0 && len(w.buf) == 0 {
+		utils.ConsoleMutex.Lock()
+		defer utils.ConsoleMutex.Unlock()
+		return fmt.Print(string(p))
+	}
+	w.buf = append(w.buf, p...)
+	if i := bytes.LastIndexByte(w.buf, '\n'); i >= 0 {
+		printLocked(string(w.buf[:i+1]))
+		w.buf = append(w.buf[:0], w.buf[i+1:]...)
+	}
+	return len(p), nil
+}
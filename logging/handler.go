@@ -0,0 +1,188 @@
+// Package logging provides a log/slog handler that colorizes levels with font, aligns the attributes,
+// and prints above the live regions so log lines scroll above running progress bars and status lines.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gngtwhh/gocui/font"
+	"github.com/gngtwhh/gocui/internal/term"
+	"github.com/gngtwhh/gocui/live"
+)
+
+// levelColors are the colors of the levels
+var levelColors = map[slog.Level]int{
+	slog.LevelDebug: font.LightBlack,
+	slog.LevelInfo:  font.Green,
+	slog.LevelWarn:  font.Yellow,
+	slog.LevelError: font.Red,
+}
+
+// Handler is a slog.Handler writing one colorized line per record.
+type Handler struct {
+	p      Property
+	color  bool
+	attrs  string // preformatted attributes added by WithAttrs
+	prefix string // group prefix of the keys, such as "req."
+	mu     *sync.Mutex
+}
+
+// NewHandler creates a handler with several modify functions.
+func NewHandler(mfs ...ModFunc) (*Handler, error) {
+	p := Property{Level: slog.LevelInfo, TimeFormat: "15:04:05.000", MsgWidth: 40}
+	for _, mf := range mfs {
+		if mf == nil {
+			return nil, fmt.Errorf("modify func cannot be nil")
+		}
+		mf(&p)
+	}
+	if p.Level == nil {
+		p.Level = slog.LevelInfo
+	}
+	h := &Handler{p: p, mu: &sync.Mutex{}}
+	switch p.ColorMode {
+	case ColorAlways:
+		h.color = true
+	case ColorAuto:
+		f, ok := p.Writer.(*os.File)
+		if p.Writer == nil {
+			f, ok = os.Stdout, true
+		}
+		h.color = ok && term.IsTerminal(int(f.Fd()))
+	}
+	return h, nil
+}
+
+// Enabled implements slog.Handler
+func (h *Handler) Enabled(_ context.Context, l slog.Level) bool {
+	return l >= h.p.Level.Level()
+}
+
+// Handle implements slog.Handler
+func (h *Handler) Handle(_ context.Context, r slog.Record) error {
+	buf := strings.Builder{}
+	if h.p.TimeFormat != "-" && !r.Time.IsZero() {
+		buf.WriteString(h.paint(r.Time.Format(h.p.TimeFormat), font.LightBlack))
+		buf.WriteByte(' ')
+	}
+	buf.WriteString(h.level(r.Level))
+	buf.WriteByte(' ')
+	if h.p.AddSource && r.PC != 0 {
+		frame, _ := runtime.CallersFrames([]uintptr{r.PC}).Next()
+		buf.WriteString(h.paint(filepath.Base(frame.File)+":"+strconv.Itoa(frame.Line), font.LightBlack))
+		buf.WriteByte(' ')
+	}
+	buf.WriteString(r.Message)
+
+	attrs := strings.Builder{}
+	attrs.WriteString(h.attrs)
+	r.Attrs(func(a slog.Attr) bool {
+		h.appendAttr(&attrs, h.prefix, a)
+		return true
+	})
+	if attrs.Len() > 0 {
+		if pad := h.p.MsgWidth - font.Width(r.Message); pad > 0 {
+			buf.WriteString(strings.Repeat(" ", pad))
+		}
+		buf.WriteString(attrs.String())
+	}
+	buf.WriteByte('\n')
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.p.Writer == nil {
+		live.Print(buf.String())
+		return nil
+	}
+	_, err := io.WriteString(h.p.Writer, buf.String())
+	return err
+}
+
+// WithAttrs implements slog.Handler
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	h2 := *h
+	buf := strings.Builder{}
+	buf.WriteString(h.attrs)
+	for _, a := range attrs {
+		h.appendAttr(&buf, h.prefix, a)
+	}
+	h2.attrs = buf.String()
+	return &h2
+}
+
+// WithGroup implements slog.Handler
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	h2 := *h
+	h2.prefix = h.prefix + name + "."
+	return &h2
+}
+
+// level returns the padded and colored name of the level.
+func (h *Handler) level(l slog.Level) string {
+	name := fmt.Sprintf("%-5s", l.String())
+	color := levelColors[slog.LevelError]
+	switch {
+	case l < slog.LevelInfo:
+		color = levelColors[slog.LevelDebug]
+	case l < slog.LevelWarn:
+		color = levelColors[slog.LevelInfo]
+	case l < slog.LevelError:
+		color = levelColors[slog.LevelWarn]
+	}
+	if l >= slog.LevelError {
+		return h.paint(name, color, font.Bold)
+	}
+	return h.paint(name, color)
+}
+
+// appendAttr writes " key=value" to buf, groups are flattened with dotted keys.
+func (h *Handler) appendAttr(buf *strings.Builder, prefix string, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+	if a.Value.Kind() == slog.KindGroup {
+		if a.Key != "" {
+			prefix += a.Key + "."
+		}
+		for _, ga := range a.Value.Group() {
+			h.appendAttr(buf, prefix, ga)
+		}
+		return
+	}
+	val := a.Value.String()
+	if a.Value.Kind() == slog.KindTime {
+		val = a.Value.Time().Format(time.RFC3339)
+	}
+	if val == "" || strings.ContainsAny(val, " \t\n\"=") {
+		val = strconv.Quote(val)
+	}
+	buf.WriteByte(' ')
+	buf.WriteString(h.paint(prefix+a.Key+"=", font.Cyan))
+	if _, isErr := a.Value.Any().(error); isErr {
+		buf.WriteString(h.paint(val, font.Red))
+	} else {
+		buf.WriteString(val)
+	}
+}
+
+// paint decorates the text if colors are enabled.
+func (h *Handler) paint(text string, style ...int) string {
+	if !h.color {
+		return text
+	}
+	return font.Decorate(text, style...)
+}
@@ -0,0 +1,69 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gngtwhh/gocui/font"
+)
+
+func TestHandler(t *testing.T) {
+	tests := []struct {
+		name string
+		mfs  []ModFunc
+		log  func(l *slog.Logger)
+		want string
+	}{
+		{"message", nil, func(l *slog.Logger) { l.Info("started") }, "INFO  started\n"},
+		{"below the level", nil, func(l *slog.Logger) { l.Debug("hidden") }, ""},
+		{"level", []ModFunc{WithLevel(slog.LevelDebug)}, func(l *slog.Logger) { l.Debug("shown") }, "DEBUG shown\n"},
+		{"aligned attributes", []ModFunc{WithMsgWidth(8)}, func(l *slog.Logger) { l.Warn("low", "disk", 5) },
+			"WARN  low      disk=5\n"},
+		{"quoted values", nil, func(l *slog.Logger) { l.Error("failed", "err", errors.New("no such file"), "empty", "") },
+			`ERROR failed err="no such file" empty=""` + "\n"},
+		{"groups", nil, func(l *slog.Logger) { l.WithGroup("req").With("id", 7).Info("done", slog.Group("res", "code", 200)) },
+			"INFO  done req.id=7 req.res.code=200\n"},
+		{"empty group", nil, func(l *slog.Logger) { l.WithGroup("").Info("done", "a", 1) }, "INFO  done a=1\n"},
+		{"time", []ModFunc{WithTimeFormat("15:04")}, func(l *slog.Logger) {
+			l.Handler().Handle(context.Background(), slog.NewRecord(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), slog.LevelInfo, "at", 0))
+		}, "03:04 INFO  at\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			mfs := append([]ModFunc{WithWriter(buf), WithTimeFormat("-"), WithMsgWidth(0)}, tt.mfs...)
+			h, err := NewHandler(mfs...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			tt.log(slog.New(h))
+			if got := buf.String(); got != tt.want {
+				t.Errorf("logged %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandlerColor(t *testing.T) {
+	buf := &bytes.Buffer{}
+	h, _ := NewHandler(WithWriter(buf), WithTimeFormat("-"), WithMsgWidth(0))
+	slog.New(h).Error("plain")
+	if strings.Contains(buf.String(), "\033") {
+		t.Errorf("colors written to a buffer: %q", buf.String())
+	}
+	buf.Reset()
+	h, _ = NewHandler(WithWriter(buf), WithTimeFormat("-"), WithMsgWidth(0), WithColor())
+	slog.New(h).Error("red", "k", "v")
+	want := font.Decorate("ERROR", font.Red, font.Bold) + " red " + font.Decorate("k=", font.Cyan) + "v\n"
+	if buf.String() != want {
+		t.Errorf("logged %q, want %q", buf.String(), want)
+	}
+	if _, err := NewHandler(nil); err == nil {
+		t.Error("no error for a nil modify func")
+	}
+}
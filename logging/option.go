@@ -0,0 +1,75 @@
+package logging
+
+import (
+	"io"
+	"log/slog"
+)
+
+// Color modes
+const (
+	ColorAuto   = iota // colors only when writing to a terminal
+	ColorAlways        // always colors
+	ColorNever         // plain text
+)
+
+// ModFunc is a function that modifies the Property of the handler.
+type ModFunc func(p *Property)
+
+// Property is the property of the handler.
+type Property struct {
+	Level      slog.Leveler // Level: the minimum level to log, default slog.LevelInfo
+	Writer     io.Writer    // Writer: where to log, default above the live regions on stdout
+	ColorMode  int          // ColorMode: ColorAuto(default), ColorAlways or ColorNever
+	TimeFormat string       // TimeFormat: the layout of the time, default "15:04:05.000", "-" omits the time
+	MsgWidth   int          // MsgWidth: messages are padded to this width so the attributes align, default 40
+	AddSource  bool         // AddSource: whether to log the source file and line
+}
+
+// WithLevel sets the minimum level to log.
+func WithLevel(l slog.Leveler) ModFunc {
+	return func(p *Property) {
+		p.Level = l
+	}
+}
+
+// WithWriter makes the handler log to w instead of above the live regions.
+func WithWriter(w io.Writer) ModFunc {
+	return func(p *Property) {
+		p.Writer = w
+	}
+}
+
+// WithPlain disables colors.
+func WithPlain() ModFunc {
+	return func(p *Property) {
+		p.ColorMode = ColorNever
+	}
+}
+
+// WithColor enables colors even if the output is not a terminal.
+func WithColor() ModFunc {
+	return func(p *Property) {
+		p.ColorMode = ColorAlways
+	}
+}
+
+// WithTimeFormat sets the layout of the time, "-" omits the time.
+func WithTimeFormat(f string) ModFunc {
+	return func(p *Property) {
+		p.TimeFormat = f
+	}
+}
+
+// WithMsgWidth sets the width messages are padded to, so the attributes align. 0 disables the padding.
+func WithMsgWidth(w int) ModFunc {
+	return func(p *Property) {
+		p.MsgWidth = max(w, 0)
+	}
+}
+
+// WithSource logs the source file and line of each record.
+func WithSource() ModFunc {
+	return func(p *Property) {
+		p.AddSource = true
+	}
+}
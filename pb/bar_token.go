@@ -8,6 +8,8 @@ import (
 	"time"
 
 	"github.com/gngtwhh/gocui/font"
+	"github.com/gngtwhh/gocui/graph"
+	"github.com/gngtwhh/gocui/spinner"
 )
 
 /**************************************************
@@ -18,8 +20,11 @@ import (
  * %total: Total progress value
  * %elapsed: The elapsed time of the progress bar
  * %rate: Speed of the progress bar
- * %spinner: A rotator character
+ * %spinner: A rotator, animated by the elapsed time
  * %bytes: Progress of writing data
+ * %msg, %label: The message set by Runner.SetMessage
+ * %{var:name}: The variable with the name set by Runner.SetVar
+ * %sparkline: The recent rates of the progress
  * &percent: Percentage of progress
  **************************************************/
 
@@ -38,6 +43,9 @@ func InitBarToken() {
 	registeredTokens["%rate"] = &TokenRate{minDelay: time.Millisecond * 100}
 	registeredTokens["%spinner"] = &TokenSpinner{}
 	registeredTokens["%bytes"] = &TokenBytes{}
+	registeredTokens["%msg"] = &TokenMessage{}
+	registeredTokens["%label"] = &TokenMessage{}
+	registeredTokens["%sparkline"] = &TokenSparkline{}
 }
 
 // token is the interface that all tokens must implement.
@@ -60,8 +68,24 @@ type TokenRate struct {
 	lastProcess int64
 }
 type TokenString struct{ payload string }
-type TokenSpinner struct{ cur int8 }
+type TokenSpinner struct{}
 type TokenBytes struct{}
+type TokenMessage struct{}
+type TokenVar struct{ name string }
+type TokenSparkline struct{}
+
+// sparkline is the rate history of the token "%sparkline" of a context.
+type sparkline struct {
+	lastTime    time.Time
+	lastCurrent int64
+	rates       []float64
+}
+
+// The token "%sparkline" samples the rate every sparkInterval, and shows the last sparkWidth samples.
+const (
+	sparkInterval = 200 * time.Millisecond
+	sparkWidth    = 12
+)
 
 // ToString implements the interface
 func (b *TokenBar) ToString(ctx *Context) string {
@@ -77,7 +101,10 @@ func (b *TokenBar) ToString(ctx *Context) string {
 	if barWidth == 0 {
 		barWidth = ctx.WindowWidth - ctx.WidthWithoutBar
 	}
-	if p.Uncertain {
+	if barWidth <= 0 { // no room for the bar, or the width of the terminal is unknown
+		return ""
+	}
+	if ctx.Indeterminate() {
 		// leftSpace := int(ctx.current)
 		// rightSpace := barWidth - leftSpace - len(p.Style.UnCertain)
 		// if leftSpace == 0 && ctx.direction == -1 {
@@ -86,7 +113,7 @@ func (b *TokenBar) ToString(ctx *Context) string {
 		// if rightSpace == 0 && ctx.direction == 1 {
 		// 	ctx.direction = -1
 		// }
-		leftSpace := int(ctx.Current) % barWidth
+		leftSpace := int(ctx.Tick) % barWidth
 		uncertainWidth := min(barWidth-leftSpace, len(p.Style.UnCertain))
 		rightSpace := max(0, barWidth-leftSpace-len(p.Style.UnCertain))
 		return font.Decorate(repeatStr(p.Style.Incomplete, leftSpace), p.Style.IncompleteColor) +
@@ -108,22 +135,29 @@ func (c *TokenCurrent) ToString(ctx *Context) string {
 }
 
 func (t *TokenTotal) ToString(ctx *Context) string {
+	if ctx.Indeterminate() {
+		return "?"
+	}
 	return strconv.FormatInt(ctx.Total, 10)
 }
 
 func (t *TokenPercent) ToString(ctx *Context) string {
-	var percent int
-	if ctx.Current == 0 {
-		percent = 0
-	} else {
-		percent = int(float64(ctx.Current) / float64(ctx.Total) * 100)
+	return percentOf(float64(ctx.Current), float64(ctx.Total))
+}
+
+// percentOf formats the ratio of current to total as an integer percentage of 3 digits, "?" if the total is unknown.
+func percentOf(current, total float64) string {
+	if total <= 0 {
+		return "  ?%"
 	}
-	// 保留2位小数
-	return fmt.Sprintf("%3d%%", percent)
+	return fmt.Sprintf("%3d%%", int(current/total*100))
 }
 
 func (t *TokenElapsed) ToString(ctx *Context) string {
 	// return fmt.Sprintf("%5.2fs", ctx.property.elapsed.Seconds())
+	if ctx.State != nil {
+		return fmt.Sprintf("%.1fs", ctx.State.Elapsed().Seconds()) // frozen once stopped
+	}
 	return fmt.Sprintf("%.1fs", time.Since(ctx.StartTime).Seconds())
 }
 
@@ -151,8 +185,15 @@ func (s *TokenString) ToString(ctx *Context) string {
 }
 
 func (s *TokenSpinner) ToString(ctx *Context) string {
-	res := "\\|/-"[s.cur : s.cur+1]
-	s.cur = (s.cur + 1) % 4
+	frames := ctx.Property.Spinner
+	if len(frames.Frames) == 0 {
+		frames = spinner.Line
+	}
+	elapsed := time.Since(ctx.StartTime)
+	res := font.Pad(frames.At(elapsed), frames.Width()) // the bar keeps its width with frames of any width
+	if colors := ctx.Property.SpinnerColors; len(colors) > 0 && frames.Interval > 0 {
+		res = font.Decorate(res, colors[int(elapsed/frames.Interval)%len(colors)])
+	}
 	return res
 }
 
@@ -168,12 +209,56 @@ func (s *TokenBytes) ToString(ctx *Context) string {
 		val := math.Floor(float64(b)/math.Pow(base, e)*10+0.5) / 10
 		return fmt.Sprintf("%.1f%s", val, unit)
 	}
-	if ctx.Property.Uncertain {
+	if ctx.Indeterminate() {
 		return calStr(ctx.Current)
 	}
 	return calStr(ctx.Current) + "/" + calStr(ctx.Total)
 }
 
+func (m *TokenMessage) ToString(ctx *Context) string {
+	if ctx.State == nil {
+		return ""
+	}
+	return ctx.State.Message()
+}
+
+func (v *TokenVar) ToString(ctx *Context) string {
+	if ctx.State == nil {
+		return ""
+	}
+	return ctx.State.Label(v.name)
+}
+
+func (s *TokenSparkline) ToString(ctx *Context) string {
+	if ctx.spark == nil {
+		ctx.spark = &sparkline{lastTime: time.Now(), lastCurrent: ctx.Current}
+	}
+	sp := ctx.spark
+	if dur := time.Since(sp.lastTime); dur >= sparkInterval {
+		sp.rates = append(sp.rates, float64(ctx.Current-sp.lastCurrent)/dur.Seconds())
+		if len(sp.rates) > sparkWidth {
+			sp.rates = sp.rates[len(sp.rates)-sparkWidth:]
+		}
+		sp.lastTime, sp.lastCurrent = time.Now(), ctx.Current
+	}
+	line := graph.Sparkline(sp.rates, sparkWidth)
+	return strings.Repeat(" ", sparkWidth-len(sp.rates)) + line // a fixed width, so the bar does not move
+}
+
+// parseVar parses the token "%{var:name}" at the beginning of format,
+// and returns the token and its length, or nil if format does not begin with it.
+func parseVar(format string) (t token, n int) {
+	const prefix = "%{var:"
+	if !strings.HasPrefix(format, prefix) {
+		return nil, 0
+	}
+	end := strings.IndexByte(format, '}')
+	if end <= len(prefix) { // no closing brace or an empty name
+		return nil, 0
+	}
+	return &TokenVar{name: format[len(prefix):end]}, end + 1
+}
+
 // unmarshalToken converts the token string to a slice of tokens.
 func unmarshalToken(format string) (ts []token, barPos []int) {
 	if len(format) == 0 {
@@ -186,13 +271,19 @@ func unmarshalToken(format string) (ts []token, barPos []int) {
 		if format[0] != '%' {
 			goto commonString
 		}
-		for legalToken, legalTokenInstance := range registeredTokens {
-			if strings.HasPrefix(format, legalToken) {
-				format = format[len(legalToken):]
-				var newToken = legalTokenInstance
-				ts = append(ts, newToken)
-				ok = true
-				break
+		if t, n := parseVar(format); t != nil {
+			format = format[n:]
+			ts = append(ts, t)
+			ok = true
+		} else {
+			for legalToken, legalTokenInstance := range registeredTokens {
+				if strings.HasPrefix(format, legalToken) {
+					format = format[len(legalToken):]
+					var newToken = legalTokenInstance
+					ts = append(ts, newToken)
+					ok = true
+					break
+				}
 			}
 		}
 		if ok && len(format) == 0 {
@@ -0,0 +1,66 @@
+package pb
+
+import (
+	"slices"
+	"testing"
+)
+
+// tokenName describes a token for comparison: its registered name, "var:" and its name, or its text.
+func tokenName(t token) string {
+	switch v := t.(type) {
+	case *TokenString:
+		return "'" + v.payload + "'"
+	case *TokenVar:
+		return "var:" + v.name
+	}
+	for name, r := range registeredTokens {
+		if r == t && name != "%label" {
+			return name
+		}
+	}
+	return "?"
+}
+
+func TestUnmarshalToken(t *testing.T) {
+	tests := []struct {
+		format string
+		want   []string
+	}{
+		{"", nil},
+		{"plain", []string{"'plain'"}},
+		{"%bar", []string{"%bar"}},
+		{"[%bar] %percent", []string{"'['", "%bar", "'] '", "%percent"}},
+		{"%{var:file}", []string{"var:file"}},
+		{"%{var:file} %bar", []string{"var:file", "' '", "%bar"}},
+		{"a%{var:x}%{var:y}b", []string{"'a'", "var:x", "var:y", "'b'"}},
+		{"%{var:}", []string{"'%{var:}'"}},
+		{"%{var:x", []string{"'%{var:x'"}},
+		{"%{msg}", []string{"'%{msg}'"}},
+		{"%unknown %msg", []string{"'%unknown '", "%msg"}},
+		{"100%", []string{"'100'", "'%'"}},
+		{"%%bar", []string{"'%'", "%bar"}},
+	}
+	for _, tt := range tests {
+		ts, _ := unmarshalToken(tt.format)
+		var got []string
+		for _, tk := range ts {
+			got = append(got, tokenName(tk))
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("unmarshalToken(%q) = %q, want %q", tt.format, got, tt.want)
+		}
+	}
+}
+
+func TestTokenVar(t *testing.T) {
+	ts, _ := unmarshalToken("%{var:file}")
+	ctx := &Context{}
+	if got := ts[0].ToString(ctx); got != "" {
+		t.Errorf("without a state = %q, want empty", got)
+	}
+	ctx.State = NewState(0)
+	ctx.State.SetLabel("file", "x.txt")
+	if got := ts[0].ToString(ctx); got != "x.txt" {
+		t.Errorf("with the label = %q, want %q", got, "x.txt")
+	}
+}
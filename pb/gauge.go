@@ -0,0 +1,373 @@
+package pb
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/gngtwhh/gocui/font"
+	"github.com/gngtwhh/gocui/graph"
+	"github.com/gngtwhh/gocui/widget"
+)
+
+// Gauges show the progress of a State as widgets other than a linear bar, to be rendered in a box or a layout.
+// They read the State at each render, so a gauge can share the State of a running bar by Runner.State.
+// The Style of a gauge is the one of the "bar" token: the complete part is drawn in CompleteColor,
+// the incomplete part in IncompleteColor, and the percentage is formatted as the token "%percent".
+
+// DefaultGaugeStyle is the style of a gauge without colors.
+var DefaultGaugeStyle = Style{Incomplete: "░", CompleteColor: font.Green, IncompleteColor: font.LightBlack}
+
+// Meter is a vertical bar filling up from the bottom, with the percentage and the label below.
+type Meter struct {
+	Rows, Cols int    // Rows, Cols: the size, default 10 rows and the width of the label, at least 4
+	Label      string // Label: the text below the percentage, none if empty
+	Style      Style  // Style: the colors, and the Incomplete character, default DefaultGaugeStyle
+
+	state *State
+}
+
+// NewMeter creates a meter of the state, a new state of the total 100 if state is nil.
+func NewMeter(state *State, label string) *Meter {
+	if state == nil {
+		state = NewState(100)
+	}
+	return &Meter{Label: label, Style: DefaultGaugeStyle, state: state}
+}
+
+// State returns the state shown by the meter.
+func (m *Meter) State() *State {
+	return m.state
+}
+
+// Measure implements widget.Widget
+func (m *Meter) Measure(c widget.Constraints) widget.Size {
+	s := widget.Size{Rows: m.Rows, Cols: m.Cols}
+	if s.Rows <= 0 {
+		s.Rows = 10
+	}
+	if s.Cols <= 0 {
+		s.Cols = max(font.Width(m.Label), 4)
+	}
+	return c.Fit(s)
+}
+
+// Render implements widget.Widget
+func (m *Meter) Render(r widget.Region) {
+	for i, line := range m.Lines(r.Rows, r.Cols) {
+		r.Line(i, line)
+	}
+}
+
+// Print prints the meter on the screen with its top left corner at (x, y).
+func (m *Meter) Print(x, y int) {
+	widget.Print(m, x, y)
+}
+
+// Lines returns the meter of rows x cols cells as lines of text.
+func (m *Meter) Lines(rows, cols int) []string {
+	text := []string{formatPercent(m.state)}
+	if m.Label != "" {
+		text = append(text, m.Label)
+	}
+	height := rows - len(text)
+	if height <= 0 || cols <= 0 {
+		return nil
+	}
+	style := gaugeStyle(m.Style)
+	incomplete := []rune(style.Incomplete)[0]
+	eighths := int(math.Round(ratio(m.state) * float64(height*8)))
+	lines := make([]string, 0, rows)
+	for i := 0; i < height; i++ {
+		level := (height - 1 - i) * 8 // the eighths below the row
+		switch {
+		case eighths >= level+8:
+			lines = append(lines, font.Decorate(strings.Repeat("█", cols), style.CompleteColor))
+		case eighths > level:
+			lines = append(lines, font.Decorate(strings.Repeat(string(font.LowerEighths[eighths-level]), cols), style.CompleteColor))
+		default:
+			lines = append(lines, font.Decorate(strings.Repeat(string(incomplete), cols), style.IncompleteColor))
+		}
+	}
+	for _, t := range text {
+		lines = append(lines, font.Center(t, cols))
+	}
+	return lines
+}
+
+// Gauge is a half-circle dial drawn with Braille, filling up from the left to the right,
+// with the percentage and the label below.
+type Gauge struct {
+	Rows, Cols int     // Rows, Cols: the size, default 6 rows and the width of a round dial
+	Label      string  // Label: the text below the percentage, none if empty
+	Style      Style   // Style: the colors, default DefaultGaugeStyle
+	Thickness  float64 // Thickness: the ratio of the width of the dial to its radius, default 0.4
+
+	state *State
+}
+
+// NewGauge creates a gauge of the state, a new state of the total 100 if state is nil.
+func NewGauge(state *State, label string) *Gauge {
+	if state == nil {
+		state = NewState(100)
+	}
+	return &Gauge{Label: label, Style: DefaultGaugeStyle, Thickness: 0.4, state: state}
+}
+
+// State returns the state shown by the gauge.
+func (g *Gauge) State() *State {
+	return g.state
+}
+
+// textRows returns the rows of the percentage and the label.
+func (g *Gauge) textRows() int {
+	if g.Label != "" {
+		return 2
+	}
+	return 1
+}
+
+// Measure implements widget.Widget
+func (g *Gauge) Measure(c widget.Constraints) widget.Size {
+	s := widget.Size{Rows: g.Rows, Cols: g.Cols}
+	if s.Rows <= 0 {
+		s.Rows = 6
+	}
+	if s.Cols <= 0 {
+		// a Braille cell is 4 pixels high and 2 wide, the dial is twice as wide as high
+		s.Cols = max((s.Rows-g.textRows())*4, font.Width(g.Label), 4)
+	}
+	return c.Fit(s)
+}
+
+// Render implements widget.Widget
+func (g *Gauge) Render(r widget.Region) {
+	for i, line := range g.Lines(r.Rows, r.Cols) {
+		r.Line(i, line)
+	}
+}
+
+// Print prints the gauge on the screen with its top left corner at (x, y).
+func (g *Gauge) Print(x, y int) {
+	widget.Print(g, x, y)
+}
+
+// Lines returns the gauge of rows x cols cells as lines of text.
+func (g *Gauge) Lines(rows, cols int) []string {
+	text := []string{formatPercent(g.state)}
+	if g.Label != "" {
+		text = append(text, g.Label)
+	}
+	height := rows - len(text)
+	if height <= 0 || cols <= 0 {
+		return nil
+	}
+	style := gaugeStyle(g.Style)
+	c := graph.NewCanvas(height, cols, graph.Braille)
+	pr, pc := c.PixelSize()
+	cx, cy := float64(pr-1), float64(pc-1)/2 // the center is at the middle of the bottom
+	outer := min(cx, cy)
+	inner := outer * (1 - min(max(g.Thickness, 0.05), 1))
+	filled := math.Pi * (1 - ratio(g.state)) // the pixels from this angle to Pi are complete
+	ring(c, cx, cy, inner, outer, func(angle float64) (int, bool) {
+		if angle < 0 || angle > math.Pi {
+			return 0, false
+		}
+		if angle >= filled && g.state.Total() > 0 {
+			return style.CompleteColor, true
+		}
+		return style.IncompleteColor, true
+	})
+	lines := c.Lines()
+	for _, t := range text {
+		lines = append(lines, font.Center(t, cols))
+	}
+	return lines
+}
+
+// Slice is a part of a donut chart.
+type Slice struct {
+	Label string
+	Value float64
+	Color int // Color: the color of the slice, default the next color of graph.Palette
+}
+
+// Donut is a ring of slices in proportion to their values drawn with Braille, clockwise from the top,
+// with a legend of the labels and the percentages on the right.
+type Donut struct {
+	Rows, Cols int     // Rows, Cols: the size, default 6 rows and the width of the ring and the legend
+	Hole       float64 // Hole: the ratio of the radius of the hole to the radius of the ring, 0 for a pie
+	Legend     bool    // Legend: whether the legend is shown
+
+	slices []Slice
+}
+
+// NewDonut creates an empty donut chart with a legend, the hole is half of the radius.
+func NewDonut() *Donut {
+	return &Donut{Hole: 0.5, Legend: true}
+}
+
+// Add adds a slice, color 0 for the next color of graph.Palette.
+func (d *Donut) Add(label string, value float64, color int) *Donut {
+	if color == font.RESET {
+		color = graph.Palette[len(d.slices)%len(graph.Palette)]
+	}
+	d.slices = append(d.slices, Slice{Label: label, Value: max(value, 0), Color: color})
+	return d
+}
+
+// Set sets the value of the slice with the label, which is added if not found.
+func (d *Donut) Set(label string, value float64) *Donut {
+	for i := range d.slices {
+		if d.slices[i].Label == label {
+			d.slices[i].Value = max(value, 0)
+			return d
+		}
+	}
+	return d.Add(label, value, 0)
+}
+
+// Measure implements widget.Widget
+func (d *Donut) Measure(c widget.Constraints) widget.Size {
+	s := widget.Size{Rows: d.Rows, Cols: d.Cols}
+	if s.Rows <= 0 {
+		s.Rows = 6
+	}
+	if s.Cols <= 0 {
+		s.Cols = s.Rows * 2
+		if legend := d.legend(); len(legend) > 0 {
+			w := 0
+			for _, line := range legend {
+				w = max(w, font.Width(line))
+			}
+			s.Cols += 1 + w
+		}
+	}
+	return c.Fit(s)
+}
+
+// Render implements widget.Widget
+func (d *Donut) Render(r widget.Region) {
+	for i, line := range d.Lines(r.Rows, r.Cols) {
+		r.Line(i, line)
+	}
+}
+
+// Print prints the donut chart on the screen with its top left corner at (x, y).
+func (d *Donut) Print(x, y int) {
+	widget.Print(d, x, y)
+}
+
+// Lines returns the donut chart of rows x cols cells as lines of text.
+func (d *Donut) Lines(rows, cols int) []string {
+	legend := d.legend()
+	legendW := 0
+	for _, line := range legend {
+		legendW = max(legendW, font.Width(line))
+	}
+	size := min(rows*2, cols) // the cells of the ring are twice as high as wide
+	if legendW > 0 && cols-legendW-1 >= 2 {
+		size = min(size, cols-legendW-1) // keep the room of the legend
+	}
+	if rows <= 0 || size <= 0 {
+		return nil
+	}
+	c := graph.NewCanvas(rows, size, graph.Braille)
+	pr, pc := c.PixelSize()
+	cx, cy := float64(pr-1)/2, float64(pc-1)/2
+	outer := min(cx, cy)
+	sum := 0.0
+	for _, s := range d.slices {
+		sum += s.Value
+	}
+	ring(c, cx, cy, outer*min(max(d.Hole, 0), 0.95), outer, func(angle float64) (int, bool) {
+		if sum <= 0 {
+			return font.LightBlack, true
+		}
+		// the fraction of the turn clockwise from the top
+		at := math.Mod(math.Pi/2-angle+4*math.Pi, 2*math.Pi) / (2 * math.Pi) * sum
+		for _, s := range d.slices {
+			if at < s.Value {
+				return s.Color, true
+			}
+			at -= s.Value
+		}
+		return d.slices[len(d.slices)-1].Color, true
+	})
+	lines := c.Lines()
+	for i := range lines {
+		if i < len(legend) && cols > size+1 {
+			lines[i] += " " + font.Truncate(legend[i], cols-size-1)
+		}
+	}
+	return lines
+}
+
+// legend returns the lines of the legend, the label and the percentage of each slice.
+func (d *Donut) legend() []string {
+	if !d.Legend {
+		return nil
+	}
+	sum := 0.0
+	for _, s := range d.slices {
+		sum += s.Value
+	}
+	lines := make([]string, 0, len(d.slices))
+	for _, s := range d.slices {
+		lines = append(lines, fmt.Sprintf("%s %s %s", font.Decorate("■", s.Color), percentOf(s.Value, sum), s.Label))
+	}
+	return lines
+}
+
+// ring sets the pixels of the canvas between the radii inner and outer around (cx, cy),
+// in the color returned by color for the angle of the pixel, skipping the pixel if it returns false.
+// The angle is in [-Pi, Pi], 0 points to the right and grows counterclockwise.
+func ring(c *graph.Canvas, cx, cy, inner, outer float64, color func(angle float64) (int, bool)) {
+	pr, pc := c.PixelSize()
+	for x := 0; x < pr; x++ {
+		for y := 0; y < pc; y++ {
+			dx, dy := cx-float64(x), float64(y)-cy
+			if r := math.Hypot(dx, dy); r < inner || r > outer+0.5 {
+				continue
+			}
+			if col, ok := color(math.Atan2(dx, dy)); ok {
+				c.SetStroke(graph.Stroke{Color: col})
+				c.Set(x, y)
+			}
+		}
+	}
+}
+
+// gaugeStyle returns the style with the defaults of DefaultGaugeStyle,
+// background colors of the bar are used as foreground colors since the gauges are drawn with characters.
+func gaugeStyle(s Style) Style {
+	if s.CompleteColor == font.RESET {
+		s.CompleteColor = DefaultGaugeStyle.CompleteColor
+	}
+	if s.IncompleteColor == font.RESET {
+		s.IncompleteColor = DefaultGaugeStyle.IncompleteColor
+	}
+	if strings.TrimSpace(s.Incomplete) == "" {
+		s.Incomplete = DefaultGaugeStyle.Incomplete
+	}
+	s.CompleteColor, s.IncompleteColor = fgColor(s.CompleteColor), fgColor(s.IncompleteColor)
+	return s
+}
+
+// fgColor returns the foreground color of a background color, other colors are returned as is.
+func fgColor(color int) int {
+	if (color >= font.BlackBg && color <= font.WhiteBg) || (color >= font.LightBlackBg && color <= font.LightWhiteBg) {
+		return color - 10
+	}
+	return color
+}
+
+// ratio returns the progress of the state in [0, 1], 0 if the total is unknown.
+func ratio(s *State) float64 {
+	return min(max(s.Percent(), 0), 1)
+}
+
+// formatPercent formats the progress of the state as the token "%percent" without the padding.
+func formatPercent(s *State) string {
+	return strings.TrimSpace(percentOf(float64(s.Current()), float64(s.Total())))
+}
@@ -0,0 +1,98 @@
+package pb
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/gngtwhh/gocui/font"
+	"github.com/gngtwhh/gocui/widget"
+)
+
+func TestMeter(t *testing.T) {
+	tests := []struct {
+		name       string
+		current    int64
+		label      string
+		rows, cols int
+		want       []string
+	}{
+		{"empty", 0, "", 3, 4, []string{"░░░░", "░░░░", " 0% "}},
+		{"half", 50, "cpu", 4, 4, []string{"░░░░", "████", "50% ", "cpu "}},
+		{"eighths", 25, "", 3, 2, []string{"░░", "▄▄", "25"}},
+		{"full", 100, "", 2, 4, []string{"████", "100%"}},
+		{"too small", 50, "cpu", 2, 4, nil},
+	}
+	for _, tt := range tests {
+		m := NewMeter(nil, tt.label)
+		m.State().SetCurrent(tt.current)
+		got := m.Lines(tt.rows, tt.cols)
+		for i := range got {
+			got[i] = font.Strip(got[i])
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%s: Lines = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestGauge(t *testing.T) {
+	g := NewGauge(nil, "disk")
+	g.State().SetCurrent(50)
+	if got := g.Measure(widget.Constraints{MaxRows: 20, MaxCols: 40}); got != (widget.Size{Rows: 6, Cols: 16}) {
+		t.Errorf("Measure = %+v, want 6 x 16", got)
+	}
+	lines := g.Lines(6, 16)
+	if len(lines) != 6 {
+		t.Fatalf("%d lines, want 6", len(lines))
+	}
+	if got := []string{lines[4], lines[5]}; !slices.Equal(got, []string{"      50%       ", "      disk      "}) {
+		t.Errorf("text = %q", got)
+	}
+	// the left half is complete, the right half incomplete
+	green, gray := font.Decorate("x", font.Green), font.Decorate("x", font.LightBlack)
+	prefix := func(s string) string { return s[:strings.Index(s, "x")] }
+	if !strings.Contains(lines[3], prefix(green)) || !strings.Contains(lines[3], prefix(gray)) {
+		t.Errorf("the dial is not filled in both colors: %q", lines[3])
+	}
+	if got := g.Lines(2, 16); got != nil {
+		t.Errorf("Lines without room for the dial = %q", got)
+	}
+}
+
+func TestDonut(t *testing.T) {
+	d := NewDonut().Add("a", 1, font.Red).Add("b", 3, font.Blue).Set("a", 1).Set("c", -2)
+	want := []string{"■  25% a", "■  75% b", "■   0% c"}
+	legend := d.legend()
+	for i := range legend {
+		legend[i] = font.Strip(legend[i])
+	}
+	if !slices.Equal(legend, want) {
+		t.Errorf("legend = %q, want %q", legend, want)
+	}
+	if got := d.Measure(widget.Constraints{MaxRows: 20, MaxCols: 40}); got != (widget.Size{Rows: 6, Cols: 21}) {
+		t.Errorf("Measure = %+v, want 6 x 21", got)
+	}
+	lines := d.Lines(6, 21)
+	if len(lines) != 6 || !strings.HasSuffix(font.Strip(lines[0]), want[0]) {
+		t.Errorf("Lines = %q", lines)
+	}
+	// a quarter of the ring is red from the top clockwise, the rest is blue
+	ring := strings.Join(lines, "")
+	if strings.Count(ring, "[31m") < 2 || strings.Count(ring, "[34m") < 4 {
+		t.Errorf("the slices are not drawn in their colors: %q", lines)
+	}
+	if lines := NewDonut().Lines(2, 4); len(lines) != 2 || !strings.Contains(lines[0], "\033[90m") {
+		t.Errorf("an empty donut is not drawn gray: %q", lines)
+	}
+}
+
+func TestGaugeStyle(t *testing.T) {
+	s := gaugeStyle(Style{CompleteColor: font.RedBg, IncompleteColor: font.LightBlueBg, Incomplete: " "})
+	if s.CompleteColor != font.Red || s.IncompleteColor != font.LightBlue || s.Incomplete != "░" {
+		t.Errorf("gaugeStyle = %+v", s)
+	}
+	if s := gaugeStyle(Style{}); s.CompleteColor != font.Green || s.IncompleteColor != font.LightBlack {
+		t.Errorf("gaugeStyle of the zero style = %+v", s)
+	}
+}
@@ -1,6 +1,9 @@
 package pb
 
-import "github.com/gngtwhh/gocui/window"
+import (
+	"github.com/gngtwhh/gocui/spinner"
+	"github.com/gngtwhh/gocui/window"
+)
 
 // ModFunc is a function that modifies the Property of the progress bar.
 type ModFunc func(p *Property)
@@ -24,7 +27,7 @@ func WithProperty(p Property) ModFunc {
 
 // WithPos sets the position of the progress bar on the screen.
 // If set, the progress bar will be placed at the specified position,
-// otherwise, it will refresh at the current line of the cursor(by default).
+// otherwise, it will refresh at the line of the cursor(by default), see WithInline.
 // param x, y: the position of the progress bar on the screen, must be within [0, screen width/height),
 // if x or y is out of range, it will not be set.
 func WithPos(x, y int) ModFunc {
@@ -48,6 +51,15 @@ func WithWidth(w int) ModFunc {
 	}
 }
 
+// WithInline makes a bar without a position refresh in a live region reserved at the line of the cursor,
+// which follows the output when the terminal scrolls, so that messages printed by Println, Printf or Writer
+// appear above the bar. Otherwise the bar is redrawn at the start of the line of the cursor by "\r".
+func WithInline() ModFunc {
+	return func(p *Property) {
+		p.Inline = true
+	}
+}
+
 // WithUncertain sets the type of progress bar to uncertain.
 func WithUncertain() ModFunc {
 	return func(p *Property) {
@@ -84,3 +96,30 @@ func WithBarWidth(w int) ModFunc {
 		p.BarWidth = w
 	}
 }
+
+// WithFullScreen makes the bar run in the full-screen mode of the window package,
+// which is entered when the bar starts and left when it stops.
+func WithFullScreen() ModFunc {
+	return func(p *Property) {
+		p.FullScreen = true
+	}
+}
+
+// WithSpinner sets the frames of the token "%spinner" to the frame set registered in the spinner package
+// with the name, such as "dots", "arc" or "moon", and the colors it cycles through.
+// If the name is not registered, the frames will not be set.
+func WithSpinner(name string, colors ...int) ModFunc {
+	return func(p *Property) {
+		if f, ok := spinner.Get(name); ok {
+			p.Spinner = f
+			p.SpinnerColors = colors
+		}
+	}
+}
+
+// WithHideFinished hides the stopped children of the bar, instead of collapsing them to their own lines.
+func WithHideFinished() ModFunc {
+	return func(p *Property) {
+		p.HideFinished = true
+	}
+}
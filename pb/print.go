@@ -0,0 +1,26 @@
+package pb
+
+import (
+	"io"
+
+	"github.com/gngtwhh/gocui/live"
+)
+
+// Println prints the operands like fmt.Println, above the running inline bars, see WithInline,
+// which are redrawn below the message instead of being overwritten.
+func Println(a ...any) {
+	live.Println(a...)
+}
+
+// Printf prints like fmt.Printf, above the running inline bars.
+func Printf(format string, a ...any) {
+	live.Printf(format, a...)
+}
+
+// Writer returns an io.Writer that prints above the running inline bars, for log.SetOutput or slog handlers:
+//
+//	log.SetOutput(pb.Writer())
+//	slog.SetDefault(slog.New(slog.NewTextHandler(pb.Writer(), nil)))
+func Writer() io.Writer {
+	return live.Writer()
+}
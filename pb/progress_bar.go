@@ -9,7 +9,10 @@ import (
 
 	"github.com/gngtwhh/gocui/cursor"
 	"github.com/gngtwhh/gocui/font"
+	"github.com/gngtwhh/gocui/live"
+	"github.com/gngtwhh/gocui/spinner"
 	"github.com/gngtwhh/gocui/utils"
+	"github.com/gngtwhh/gocui/widget"
 	"github.com/gngtwhh/gocui/window"
 )
 
@@ -68,9 +71,16 @@ type Property struct {
 	BarWidth   int    // BarWidth: The render width of the token:"%bar"
 	Width      int    // Width: The maximum width of the progress bar displayed on the terminal.
 
-	Uncertain bool // Uncertain: Whether the progress bar is Uncertain, default: false
-	Bytes     bool // Type: Whether the progress bar is used for bytes writer, default: false
-	BindPos   bool // Whether bind the absolute pos, PosX and PosY are valid only when BindPos is true
+	Uncertain  bool // Uncertain: Whether the progress bar is Uncertain, default: false
+	Bytes      bool // Type: Whether the progress bar is used for bytes writer, default: false
+	BindPos    bool // Whether bind the absolute pos, PosX and PosY are valid only when BindPos is true
+	Inline     bool // Inline: Whether an unbound bar is drawn in a live region at the cursor, default: false
+	FullScreen bool // FullScreen: Whether the bar runs in the full-screen mode, default: false
+
+	HideFinished bool // HideFinished: Whether the stopped children are hidden instead of collapsed, default: false
+
+	Spinner       spinner.Frames // Spinner: The frames of the token "%spinner", default: spinner.Line
+	SpinnerColors []int          // SpinnerColors: The colors cycled by the token "%spinner", default: none
 
 	formatChanged bool // Indicates the change in format when updating property
 }
@@ -92,17 +102,42 @@ type ProgressBar struct {
 
 // Context is a Context created when the progress bar is running.
 // Each progress bar instance can create several contexts for reuse.
+// The progress is kept in State, Current and Total are the snapshot of it taken for each render.
 type Context struct {
-	Property        Property      // Copy of static progress bar property
-	tokens          []token       // Copy of static progress bar tokens
-	Total           int64         // total: Only available when Uncertain is false or Bytes is true
-	Current         int64         // current progress
-	WindowWidth     int           // window width, set by window.GetConsoleSize()
-	WidthWithoutBar int           // accumulated render width without bar
-	StartTime       time.Time     // start time
-	Interrupt       chan struct{} // interrupt channel to stop running
+	Property Property // Copy of static progress bar property
+	tokens   []token  // Copy of static progress bar tokens
+	State    *State   // the progress data, safe for concurrent use
+	// Total is the total of the snapshot: 0 if unknown, the bar is indeterminate until the total is known.
+	//
+	// Deprecated: use State.Total, this is only the snapshot taken for the last render.
+	Total int64
+	// Current is the current progress of the snapshot.
+	//
+	// Deprecated: use State.Current, this is only the snapshot taken for the last render.
+	Current         int64
+	Tick            int64 // the number of renders while indeterminate, moves the uncertain bar
+	WindowWidth     int   // window width, set by window.GetConsoleSize()
+	WidthWithoutBar int   // accumulated render width without bar
+	// StartTime is the start time of the progress.
+	//
+	// Deprecated: use State.StartTime.
+	StartTime time.Time
+	Interrupt chan struct{} // interrupt channel to stop running
 	// Direction: for UnCertain bar to update, 1(default) for increasing, -1 for decreasing, only available when UnCertain is true
 	Direction int
+
+	region   *widget.Region // the region the bar is bound to when rendered as a widget
+	live     *live.Region   // the live region of an inline bar
+	stopOnce *sync.Once     // ensures the stop work is done once
+	mu       *sync.Mutex    // serializes the renders, locked before the mu of the children
+
+	parent   *Context   // the parent of a child bar, which prints it
+	children []*Context // the children aggregated into the progress of the bar
+	weight   int64      // the share of a child in the progress of its parent, 0 for its total
+	lines    []string   // the lines of the last render, including the children
+	spark    *sparkline // the rate history of the token "%sparkline"
+	own      int        // the number of the lines of the bar itself in lines
+	drawn    int        // the number of the lines printed at the cursor by the last print, without a live region
 }
 
 // BytesWriter implements io.Writer interface,
@@ -112,6 +147,8 @@ type BytesWriter struct {
 	bytesChan chan int
 	// closeCh indicate that the BytesWriter has been closed
 	closeCh chan struct{}
+	// state is the progress of the bar the BytesWriter writes to, nil if not running
+	state *State
 }
 
 // Runner holds the necessary information to run a progress bar.
@@ -123,7 +160,6 @@ type Runner struct {
 
 // Update updates the progress bar's current value.
 func (r *Runner) Update(value int64) {
-	value = min(max(0, value), r.ctx.Total)
 	r.ctx.updateCurrentTo(value)
 	r.ctx.Print()
 }
@@ -134,25 +170,99 @@ func (r *Runner) UpdateAdd(value int64) {
 	r.ctx.Print()
 }
 
+// SetTotal sets the total of the running bar, the current value is clamped to it.
+// A bar started with an unknown total switches from indeterminate to determinate once the total is set,
+// setting it to 0 makes the bar indeterminate again.
+func (r *Runner) SetTotal(n int64) {
+	r.ctx.State.SetTotal(n)
+	r.ctx.Print()
+}
+
+// IncrTotal adds n to the total of the running bar, for work discovered on the fly,
+// such as the files found while crawling directories.
+func (r *Runner) IncrTotal(n int64) {
+	r.ctx.State.AddTotal(n)
+	r.ctx.Print()
+}
+
+// Measure implements widget.Widget, so that a Runner can be placed inside a box or a layout.
+// A bar with a Width fills at most Width columns, otherwise it fills all the available columns.
+func (r *Runner) Measure(c widget.Constraints) widget.Size {
+	cols := r.ctx.Property.Width
+	if cols <= 0 {
+		cols = c.MaxCols
+	}
+	if cols <= 0 {
+		cols = r.ctx.WindowWidth
+	}
+	return c.Fit(widget.Size{Rows: r.ctx.lineCount(), Cols: cols})
+}
+
+// Render implements widget.Widget.
+// It binds the bar to the region and prints it, later updates will be printed in place
+// without touching anything around the bar.
+func (r *Runner) Render(region widget.Region) {
+	if region.Rows <= 0 || region.Cols <= 0 {
+		return
+	}
+	r.ctx.mu.Lock()
+	r.ctx.region = &region
+	r.ctx.Property.Width = region.Cols
+	r.ctx.WindowWidth = region.Cols
+	r.ctx.mu.Unlock()
+	r.ctx.Print()
+}
+
 // Stop stops the progress bar running instance.
+// A stopped child is collapsed in the tree of its parent.
 func (r *Runner) Stop() {
-	// currently doing nothing
+	r.ctx.stop()
+	if r.ctx.parent != nil {
+		r.ctx.Print()
+	}
+}
+
+// SetMessage sets the message shown by the tokens "%msg" and "%label", such as "Downloading foo.tar.gz".
+// A message too long for the line is truncated, so the bar never wraps.
+func (r *Runner) SetMessage(msg string) {
+	r.ctx.State.SetMessage(msg)
+	r.ctx.Print()
+}
+
+// SetVar sets the variable shown by the token "%{var:name}", it is the label of the state with the name.
+// A value too long for the line is truncated, so the bar never wraps.
+func (r *Runner) SetVar(name, value string) {
+	r.ctx.State.SetLabel(name, value)
+	r.ctx.Print()
+}
+
+// State returns the state of the running instance, which can be read by other renderers,
+// such as a plain log or a metrics exporter, while the bar is running.
+func (r *Runner) State() *State {
+	return r.ctx.State
 }
 
 func NewContext(p *ProgressBar) Context {
 	style := make([]token, len(p.tokens))
 	copy(style, p.tokens)
 
+	state := NewState(0)
 	ctx := Context{
 		Property: p.property,
 		tokens:   style,
+		State:    state,
 		// barPos:          DefaultBarPos,
 		Current:   0,
-		StartTime: time.Now(),
+		StartTime: state.StartTime(),
 		Interrupt: make(chan struct{}),
 		Direction: 1,
+		stopOnce:  &sync.Once{},
+		mu:        &sync.Mutex{},
 	}
 	ctx.WindowWidth, _ = window.GetConsoleSize()
+	if ctx.Property.Width > 0 && ctx.Property.Width < ctx.WindowWidth {
+		ctx.WindowWidth = ctx.Property.Width
+	}
 	return ctx
 }
 
@@ -176,8 +286,32 @@ func (bw *BytesWriter) update(b []byte) error {
 		return errors.New("channel closed")
 	default:
 	}
-	bw.bytesChan <- len(b)
-	return nil
+	// bytesChan is never closed, the send gives up once the writer is closed by the bar meanwhile
+	select {
+	case <-bw.closeCh:
+		return errors.New("channel closed")
+	case bw.bytesChan <- len(b):
+		return nil
+	}
+}
+
+// SetTotal sets the total bytes of the running bar, for a size known after the bar started,
+// setting it to 0 makes the bar indeterminate again. The writer is closed once the total is reached.
+func (bw *BytesWriter) SetTotal(n int64) error {
+	if bw.state == nil {
+		return errors.New("the BytesWriter is not running")
+	}
+	bw.state.SetTotal(n)
+	return bw.update(nil) // renders the bar with the new total
+}
+
+// IncrTotal adds n to the total bytes of the running bar, for data discovered on the fly.
+func (bw *BytesWriter) IncrTotal(n int64) error {
+	if bw.state == nil {
+		return errors.New("the BytesWriter is not running")
+	}
+	bw.state.AddTotal(n)
+	return bw.update(nil)
 }
 
 // close stop the BytesWriter producer
@@ -188,7 +322,6 @@ func (bw *BytesWriter) close() error {
 	default:
 	}
 	close(bw.closeCh)
-	close(bw.bytesChan)
 	return nil
 }
 
@@ -211,7 +344,7 @@ func NewProgressBar(style string, mfs ...ModFunc) (pb *ProgressBar, err error) {
 		property.BarWidth = 0 // default 0 means full width
 	}
 	x, _ := window.GetConsoleSize()
-	if property.Width <= 0 && property.Width > x {
+	if x > 0 && property.Width > x { // 0 keeps filling the available columns
 		property.Width = x
 	}
 	if property.Style.Complete == "" {
@@ -269,7 +402,7 @@ func (p *ProgressBar) UpdateProperty(mfs ...ModFunc) (NewBar *ProgressBar, err e
 		property.BarWidth = 0 // default 0 means full width
 	}
 	x, _ := window.GetConsoleSize()
-	if property.Width <= 0 && property.Width > x {
+	if x > 0 && property.Width > x { // 0 keeps filling the available columns
 		property.Width = x
 	}
 	if property.Style.Complete == "" {
@@ -298,80 +431,226 @@ func (p *ProgressBar) UpdateProperty(mfs ...ModFunc) (NewBar *ProgressBar, err e
 	return p, nil
 }
 
+// setTotal sets the total of the progress before it starts.
+func (ctx *Context) setTotal(n int64) {
+	ctx.State.total.Store(max(n, 0))
+	ctx.Total = ctx.State.Total()
+}
+
+// restart resets the start time of the progress.
+func (ctx *Context) restart() {
+	ctx.State.restart()
+	ctx.StartTime = ctx.State.StartTime()
+}
+
+// enterFullScreen enters the full-screen mode if the bar runs in it, stop leaves it.
+func (ctx *Context) enterFullScreen() {
+	if ctx.Property.FullScreen {
+		window.EnterFullScreen()
+	}
+}
+
 // updateCurrent increase the current progress without printing the progress bar.
+// The state clamps the progress to the total if the total is known.
 func (ctx *Context) updateCurrent() {
-	if ctx.Property.Uncertain {
-		// ctx.current += int64(ctx.direction)
-		// if rightSpace := ctx.property.barWidth -ctx.current - len(ctx.property.UnCertain); rightSpace <= 0 && ctx.direction == 1 {
-		// 	ctx.current = ctx.total
-		// 	ctx.direction = -ctx.direction
-		// } else if ctx.current <= 0 && ctx.direction == -1 {
-		// 	ctx.current = 0
-		// 	ctx.direction = -ctx.direction
-		// }
-		ctx.Current++
-	} else {
-		// common bar use total to update the current progress
-		ctx.Current = min(ctx.Current+1, ctx.Total)
-	}
+	ctx.State.Add(1)
 }
 
 // updateCurrentWithAdd increase the current progress by add
 func (ctx *Context) updateCurrentWithAdd(add int64) {
-	if ctx.Property.Uncertain {
-		ctx.updateCurrent() // just add 1 for uncertain progress bar
-	} else {
-		ctx.Current = min(ctx.Current+add, ctx.Total) // common bar use total to update the current progress
-	}
+	ctx.State.Add(add)
 }
 
 // updateCurrentTo update the current progress to value
 func (ctx *Context) updateCurrentTo(value int64) {
-	if ctx.Property.Uncertain && int64(value) != ctx.Current {
-		ctx.updateCurrent() // just add 1 for uncertain progress bar
-	} else {
-		ctx.Current = min(max(int64(value), 0), ctx.Total)
-	}
+	ctx.State.SetCurrent(value)
+}
+
+// Indeterminate reports whether the total of the progress is unknown, as of the last render.
+// An indeterminate bar renders the moving uncertain style instead of the completed part.
+func (ctx *Context) Indeterminate() bool {
+	return ctx.Total <= 0
 }
 
 // Print prints the current progress of the progress bar.
+// A child bar is printed by its root, as a part of the tree.
 func (ctx *Context) Print() {
-	var payloadBuilder0 strings.Builder
-	payloadBuilder1 := strings.Builder{}
-	var barToken *TokenBar
-	for _, t := range ctx.tokens {
-		if _, ok := t.(*TokenBar); ok && barToken == nil {
-			barToken = t.(*TokenBar)
-			payloadBuilder0 = payloadBuilder1
-			payloadBuilder1 = strings.Builder{}
-		} else {
-			payloadBuilder1.WriteString(t.ToString(ctx))
+	ctx.mu.Lock()
+	if ctx.parent != nil {
+		ctx.render()
+		ctx.mu.Unlock()
+		ctx.parent.Print()
+		return
+	}
+	defer ctx.mu.Unlock()
+	lines := ctx.render()
+
+	if ctx.region != nil {
+		for i, line := range lines {
+			ctx.region.Line(i, line)
 		}
+		return
+	}
+	if ctx.Property.Inline && !ctx.Property.BindPos {
+		// render in a live region at the cursor, which follows the output when the terminal scrolls
+		if ctx.live == nil {
+			ctx.live = live.New(len(lines))
+		}
+		ctx.live.Set(lines...)
+		return
 	}
-	ctx.WidthWithoutBar = payloadBuilder0.Len() + payloadBuilder1.Len()
-	barStr := barToken.ToString(ctx)
 
 	utils.ConsoleMutex.Lock() // Lock the cursor
 	defer utils.ConsoleMutex.Unlock()
-	{
-		// window.ClearLine(-1)
+	if !ctx.Property.BindPos && ctx.drawn > 1 {
+		cursor.Up(ctx.drawn - 1) // back to the first line of the last print
+	}
+	ctx.drawn = len(lines)
+	for i, line := range lines {
 		if ctx.Property.BindPos {
-			cursor.GotoXY(ctx.Property.PosX, ctx.Property.PosY)
-		} else {
+			cursor.GotoXY(ctx.Property.PosX+i, ctx.Property.PosY)
+		} else if i == 0 {
 			fmt.Print("\r")
+		} else {
+			fmt.Print("\n\r")
 		}
-		fmt.Print(payloadBuilder0.String())
-		fmt.Print(barStr)
-		fmt.Print(payloadBuilder1.String())
-		if ctx.Property.BarWidth != 0 {
+		fmt.Print(line)
+		if ctx.Property.BarWidth != 0 && ctx.Property.Width <= 0 {
 			window.ClearLineAfterCursor()
 		}
 	}
 }
 
+// render renders the lines of the bar followed by the lines of its children, ctx.mu must be held.
+func (ctx *Context) render() []string {
+	ctx.aggregate()
+	ctx.Current, ctx.Total = ctx.State.Current(), ctx.State.Total()
+	if ctx.Indeterminate() {
+		ctx.Tick++
+	}
+
+	parts := make([]string, len(ctx.tokens))
+	barIdx := -1 // index of the first "%bar"
+	for i, t := range ctx.tokens {
+		if _, ok := t.(*TokenBar); ok && barIdx < 0 {
+			barIdx = i
+		} else {
+			parts[i] = t.ToString(ctx)
+		}
+	}
+	ctx.fit(parts, barIdx)
+	var pre, post, barStr string
+	if barIdx < 0 {
+		pre = strings.Join(parts, "")
+	} else {
+		pre, post = strings.Join(parts[:barIdx], ""), strings.Join(parts[barIdx+1:], "")
+	}
+	// the format may span several lines, only the line of the bar counts for its width
+	preLine, postLine := pre[strings.LastIndex(pre, "\n")+1:], post
+	if i := strings.Index(post, "\n"); i >= 0 {
+		postLine = post[:i]
+	}
+	ctx.WidthWithoutBar = font.Width(preLine) + font.Width(postLine)
+	if barIdx >= 0 {
+		barStr = ctx.tokens[barIdx].ToString(ctx)
+	}
+	lines := strings.Split(pre+barStr+post, "\n")
+	if ctx.Property.Width > 0 {
+		for i := range lines {
+			lines[i] = font.Pad(lines[i], ctx.Property.Width) // never draw outside the given width
+		}
+	}
+	ctx.own = len(lines)
+	ctx.lines = append(lines, ctx.childLines()...)
+	return ctx.lines
+}
+
+// minBarWidth is the width kept for a "%bar" filling the line when the messages are truncated.
+const minBarWidth = 10
+
+// fit truncates the messages and variables, the widest first, so that no line is wider than the window,
+// and the line of the bar keeps room for the bar, so the bar never wraps.
+// parts are the rendered tokens, the bar at barIdx is not rendered yet.
+func (ctx *Context) fit(parts []string, barIdx int) {
+	width := ctx.WindowWidth
+	if width <= 0 {
+		return
+	}
+	lineOf := make([]int, len(parts)) // the line each part starts on
+	n := 0
+	for i, part := range parts {
+		lineOf[i] = n
+		n += strings.Count(part, "\n")
+	}
+	for l, line := range strings.Split(strings.Join(parts, ""), "\n") {
+		overflow := font.Width(line) - width
+		if barIdx >= 0 && lineOf[barIdx] == l {
+			if ctx.Property.BarWidth > 0 {
+				overflow += ctx.Property.BarWidth
+			} else {
+				overflow += minBarWidth
+			}
+		}
+		for overflow > 0 {
+			// cut the widest one down, not below the next widest, so short values are kept as long as possible
+			widest, w, next := -1, 0, 0
+			for i, part := range parts {
+				switch ctx.tokens[i].(type) {
+				case *TokenMessage, *TokenVar:
+				default:
+					continue
+				}
+				if pw := font.Width(part); lineOf[i] == l && pw > 0 {
+					if pw > w {
+						widest, w, next = i, pw, w
+					} else {
+						next = max(next, pw)
+					}
+				}
+			}
+			if widest < 0 {
+				break
+			}
+			keep := max(w-overflow, next-1, 0)
+			parts[widest] = ellipsis(parts[widest], keep)
+			overflow -= w - font.Width(parts[widest])
+		}
+	}
+}
+
+// ellipsis truncates the text to width columns, ending with "…" if it was cut.
+func ellipsis(text string, width int) string {
+	if font.Width(text) <= width {
+		return text
+	}
+	if width <= 1 {
+		return strings.Repeat("…", width)
+	}
+	return font.Truncate(text, width-1) + "…"
+}
+
+// lineCount returns the number of lines of the format.
+func (ctx *Context) lineCount() int {
+	n := 1
+	for _, t := range ctx.tokens {
+		if s, ok := t.(*TokenString); ok {
+			n += strings.Count(s.payload, "\n")
+		}
+	}
+	return n
+}
+
 // Stop stops the progress bar.
 func (ctx *Context) stop() {
-	// currently do nothing
+	ctx.stopOnce.Do(func() {
+		ctx.State.finish()
+		if ctx.live != nil {
+			ctx.live.Close()
+		}
+		if ctx.Property.FullScreen {
+			window.ExitFullScreen()
+		}
+	})
 
 	// ctx.interrupt <- struct{}{}
 	// p.rw.Lock()
@@ -396,16 +675,17 @@ func (p *ProgressBar) iter(n int) (iter <-chan int64, stop chan<- struct{}) {
 			return ch, nil
 		}
 		ctx = NewContext(p)
-		ctx.Total = int64(n)
+		ctx.setTotal(int64(n))
 		// p.running++
 	}
 	p.rw.Unlock()
 
-	ctx.StartTime = time.Now()
+	ctx.restart()
+	ctx.enterFullScreen()
 	go func() {
 		defer ctx.stop()
 		defer close(ch)
-		for i := ctx.Current; i <= ctx.Total; i++ {
+		for i := ctx.State.Current(); i <= ctx.State.Total(); i++ {
 			ctx.Print()
 			select {
 			case ch <- i:
@@ -419,16 +699,18 @@ func (p *ProgressBar) iter(n int) (iter <-chan int64, stop chan<- struct{}) {
 }
 
 // Start starts an progress bar, and returns a *Runner instance to control the progress bar.
-// This method should not be called if the bar is uncertain.
+// If n <= 0 or the bar is uncertain, the total is unknown and the bar is indeterminate
+// until the total is set by Runner.SetTotal or Runner.IncrTotal.
 func (p *ProgressBar) Start(n int) (r *Runner, err error) {
 	if p.property.Uncertain {
-		return nil, errors.New("the bar is uncertain")
+		n = 0
 	}
 	p.rw.Lock()
 	// p.running++
 	ctx := NewContext(p)
 	p.rw.Unlock()
-	ctx.Total = int64(n)
+	ctx.setTotal(int64(n))
+	ctx.enterFullScreen()
 	r = &Runner{
 		bar: p,
 		ctx: &ctx,
@@ -469,7 +751,8 @@ func (p *ProgressBar) Run(period time.Duration) (stop chan<- struct{}) {
 	}
 	p.rw.Unlock()
 
-	ctx.StartTime = time.Now()
+	ctx.restart()
+	ctx.enterFullScreen()
 	ticker := time.NewTicker(period)
 
 	go func() {
@@ -477,8 +760,7 @@ func (p *ProgressBar) Run(period time.Duration) (stop chan<- struct{}) {
 		for {
 			select {
 			case <-ticker.C:
-				ctx.Print()
-				ctx.updateCurrent()
+				ctx.Print() // moves the uncertain bar
 			case <-ctx.Interrupt: // interrupt got a signal or closed
 				ticker.Stop()
 				return
@@ -489,8 +771,9 @@ func (p *ProgressBar) Run(period time.Duration) (stop chan<- struct{}) {
 }
 
 // RunWithWriter automatically start a progress bar with writing bytes data.
-// param n: the total bytes to write.
+// param n: the total bytes to write, 0 if unknown, it can be set later by the SetTotal of the writer.
 // It returns a writer for user to write data and a stop channel indicate exit.
+// The writer is closed once the total is reached, a writer with an unknown total runs until stopped.
 // This method should not be called if the progress bar is not with writer or is uncertain.
 func (p *ProgressBar) RunWithWriter(n int64) (writer *BytesWriter, stop chan<- struct{}) {
 	if p.property.Uncertain {
@@ -505,14 +788,16 @@ func (p *ProgressBar) RunWithWriter(n int64) (writer *BytesWriter, stop chan<- s
 			return
 		}
 		ctx = NewContext(p)
-		ctx.Total = n // n bytes to receive
+		ctx.setTotal(n) // n bytes to receive
 		// p.running++
 	}
 	p.rw.Unlock()
 
-	ctx.StartTime = time.Now()
+	ctx.restart()
+	ctx.enterFullScreen()
 
 	bw := NewBytesWriter()
+	bw.state = ctx.State
 
 	go func() {
 		defer ctx.stop()
@@ -522,7 +807,7 @@ func (p *ProgressBar) RunWithWriter(n int64) (writer *BytesWriter, stop chan<- s
 			case add := <-bw.bytesChan:
 				ctx.updateCurrentWithAdd(int64(add))
 				ctx.Print()
-				if ctx.Current == ctx.Total {
+				if total := ctx.State.Total(); total > 0 && ctx.State.Current() >= total {
 					bw.close()
 					return
 				}
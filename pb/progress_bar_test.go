@@ -0,0 +1,113 @@
+package pb
+
+import (
+	"io"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/gngtwhh/gocui/window"
+)
+
+// capture returns what f writes to the standard output.
+func capture(t *testing.T, f func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+	out := make(chan string)
+	go func() {
+		b, _ := io.ReadAll(r)
+		out <- string(b)
+	}()
+	f()
+	w.Close()
+	return <-out
+}
+
+func TestPrint(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		mfs    []ModFunc
+		want   []string // the output of two prints
+	}{
+		{"line of the cursor", "a", nil, []string{"\ra", "\ra"}},
+		{"several lines", "a\nb", nil, []string{"\ra\n\rb", "\033[1A\ra\n\rb"}},
+		{"inline", "a", []ModFunc{WithInline()}, []string{
+			"\r\033[2K\033[J\r\r\033[2Ka\033[J", "\r\r\033[2Ka\033[J",
+		}},
+	}
+	for _, tt := range tests {
+		bar, err := NewProgressBar(tt.format, tt.mfs...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ctx := NewContext(bar)
+		for i, want := range tt.want {
+			if out := capture(t, ctx.Print); out != want {
+				t.Errorf("%s: print %d printed %q, want %q", tt.name, i, out, want)
+			}
+		}
+		capture(t, ctx.stop)
+	}
+}
+
+func TestBytesWriterClose(t *testing.T) {
+	bar, err := NewProgressBar("%bar", WithWriter())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the writers and the totals race with the bar closing the writer once the total is reached
+	for range 10 {
+		w, _ := bar.RunWithWriter(64)
+		var wg sync.WaitGroup
+		for range 4 {
+			wg.Add(3)
+			go func() {
+				defer wg.Done()
+				for w.update([]byte{0}) == nil {
+				}
+			}()
+			go func() {
+				defer wg.Done()
+				for w.SetTotal(64) == nil {
+				}
+			}()
+			go func() {
+				defer wg.Done()
+				for w.IncrTotal(0) == nil {
+				}
+			}()
+		}
+		wg.Wait()
+		if err := w.close(); err == nil {
+			t.Fatal("the writer is still open once the total is reached")
+		}
+	}
+}
+
+func TestFullScreen(t *testing.T) {
+	bar, err := NewProgressBar("%bar", WithFullScreen())
+	if err != nil {
+		t.Fatal(err)
+	}
+	capture(t, func() { NewContext(bar) })
+	if window.IsFullScreen() {
+		t.Fatal("a new context entered the full-screen mode before the bar started")
+	}
+	var r *Runner
+	capture(t, func() { r, _ = bar.Start(10) })
+	if !window.IsFullScreen() {
+		t.Error("the full-screen mode is not entered when the bar starts")
+	}
+	capture(t, r.Stop)
+	capture(t, r.Stop)
+	if window.IsFullScreen() {
+		t.Error("the full-screen mode is not left when the bar stops")
+	}
+}
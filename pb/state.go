@@ -0,0 +1,218 @@
+package pb
+
+import (
+	"maps"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Status of a progress
+const (
+	Running = iota // the progress is running
+	Done           // the progress was stopped after reaching its total, or stopped without a known total
+	Aborted        // the progress was stopped before reaching its known total
+)
+
+// State is the data of a progress, separated from its presentation.
+// All the methods are safe for concurrent use, so one state can be updated by workers
+// while it is read by the terminal bar, a plain log or a metrics exporter at the same time.
+type State struct {
+	current atomic.Int64
+	total   atomic.Int64
+	status  atomic.Int32
+
+	mu        sync.RWMutex
+	startTime time.Time
+	stopTime  time.Time
+	message   string
+	labels    map[string]string
+}
+
+// Snapshot is a consistent copy of a State at a moment.
+type Snapshot struct {
+	Current, Total      int64
+	Status              int
+	StartTime, StopTime time.Time // StopTime is zero while running
+	Message             string
+	Labels              map[string]string
+}
+
+// NewState creates a running state with the total, a total <= 0 means the total is unknown.
+func NewState(total int64) *State {
+	s := &State{startTime: time.Now(), labels: make(map[string]string)}
+	s.total.Store(max(total, 0))
+	return s
+}
+
+// Current returns the current progress.
+func (s *State) Current() int64 {
+	return s.current.Load()
+}
+
+// Total returns the total progress, 0 if unknown.
+func (s *State) Total() int64 {
+	return s.total.Load()
+}
+
+// Percent returns the ratio of the current progress to the total in [0, 1], 0 if the total is unknown.
+func (s *State) Percent() float64 {
+	cur, total := s.Current(), s.Total()
+	if total <= 0 {
+		return 0
+	}
+	return min(float64(cur)/float64(total), 1)
+}
+
+// SetCurrent sets the current progress to value, clamped to [0, total] if the total is known.
+func (s *State) SetCurrent(value int64) {
+	if total := s.Total(); total > 0 {
+		value = min(value, total)
+	}
+	s.current.Store(max(value, 0))
+}
+
+// SetTotal sets the total progress, a total <= 0 means the total is unknown.
+// The current progress is clamped to the new total.
+func (s *State) SetTotal(total int64) {
+	total = max(total, 0)
+	s.total.Store(total)
+	s.clamp(total)
+}
+
+// AddTotal adds n to the total progress, for work discovered on the fly, and returns the new total.
+// The current progress is clamped to the new total.
+func (s *State) AddTotal(n int64) int64 {
+	for {
+		old := s.total.Load()
+		total := max(old+n, 0)
+		if s.total.CompareAndSwap(old, total) {
+			s.clamp(total)
+			return total
+		}
+	}
+}
+
+// clamp clamps the current progress to the total if the total is known.
+func (s *State) clamp(total int64) {
+	for total > 0 {
+		old := s.current.Load()
+		if old <= total || s.current.CompareAndSwap(old, total) {
+			return
+		}
+	}
+}
+
+// Add adds n to the current progress, clamped to [0, total] if the total is known, and returns the new value.
+func (s *State) Add(n int64) int64 {
+	for {
+		old := s.current.Load()
+		value := max(old+n, 0)
+		if total := s.Total(); total > 0 {
+			value = min(value, total)
+		}
+		if s.current.CompareAndSwap(old, value) {
+			return value
+		}
+	}
+}
+
+// Status returns the status of the progress: Running, Done or Aborted.
+func (s *State) Status() int {
+	return int(s.status.Load())
+}
+
+// StartTime returns the time the progress started.
+func (s *State) StartTime() time.Time {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.startTime
+}
+
+// StopTime returns the time the progress stopped, zero while running.
+func (s *State) StopTime() time.Time {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.stopTime
+}
+
+// Elapsed returns the time since the progress started, until it stopped.
+func (s *State) Elapsed() time.Duration {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.stopTime.IsZero() {
+		return time.Since(s.startTime)
+	}
+	return s.stopTime.Sub(s.startTime)
+}
+
+// Message returns the message of the progress.
+func (s *State) Message() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.message
+}
+
+// SetMessage sets the message of the progress, a single line, newlines are replaced by spaces.
+func (s *State) SetMessage(msg string) {
+	msg = strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ").Replace(msg)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.message = msg
+}
+
+// Label returns the value of the label with the key, "" if not set.
+func (s *State) Label(key string) string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.labels[key]
+}
+
+// SetLabel sets the label with the key to value, an empty value removes the label.
+func (s *State) SetLabel(key, value string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if value == "" {
+		delete(s.labels, key)
+		return
+	}
+	s.labels[key] = value
+}
+
+// Snapshot returns a copy of the state.
+func (s *State) Snapshot() Snapshot {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return Snapshot{
+		Current:   s.Current(),
+		Total:     s.Total(),
+		Status:    s.Status(),
+		StartTime: s.startTime,
+		StopTime:  s.stopTime,
+		Message:   s.message,
+		Labels:    maps.Clone(s.labels),
+	}
+}
+
+// restart resets the start time, used when a bar begins rendering later than its state was created.
+func (s *State) restart() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.startTime = time.Now()
+}
+
+// finish records the stop time and the final status, only the first call takes effect.
+func (s *State) finish() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.stopTime.IsZero() {
+		return
+	}
+	s.stopTime = time.Now()
+	if total := s.Total(); total <= 0 || s.Current() >= total {
+		s.status.Store(Done)
+	} else {
+		s.status.Store(Aborted)
+	}
+}
@@ -0,0 +1,98 @@
+package pb
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestState(t *testing.T) {
+	tests := []struct {
+		name    string
+		total   int64
+		update  func(s *State)
+		current int64
+		wantTot int64
+		percent float64
+		status  int
+	}{
+		{"new", 10, func(s *State) {}, 0, 10, 0, Running},
+		{"unknown total", -5, func(s *State) { s.Add(7) }, 7, 0, 0, Running},
+		{"add", 10, func(s *State) { s.Add(4) }, 4, 10, 0.4, Running},
+		{"add clamped to total", 10, func(s *State) { s.Add(15) }, 10, 10, 1, Running},
+		{"add clamped to zero", 10, func(s *State) { s.Add(3); s.Add(-5) }, 0, 10, 0, Running},
+		{"set current clamped", 10, func(s *State) { s.SetCurrent(20) }, 10, 10, 1, Running},
+		{"set current negative", 10, func(s *State) { s.SetCurrent(-1) }, 0, 10, 0, Running},
+		{"set total clamps current", 0, func(s *State) { s.Add(8); s.SetTotal(4) }, 4, 4, 1, Running},
+		{"set total unknown", 10, func(s *State) { s.Add(8); s.SetTotal(-1) }, 8, 0, 0, Running},
+		{"add total", 10, func(s *State) { s.Add(10); s.AddTotal(10) }, 10, 20, 0.5, Running},
+		{"add total below zero", 10, func(s *State) { s.AddTotal(-15) }, 0, 0, 0, Running},
+		{"add total clamps current", 10, func(s *State) { s.Add(10); s.AddTotal(-5) }, 5, 5, 1, Running},
+		{"done", 10, func(s *State) { s.Add(10); s.finish() }, 10, 10, 1, Done},
+		{"aborted", 10, func(s *State) { s.Add(5); s.finish() }, 5, 10, 0.5, Aborted},
+		{"done without total", 0, func(s *State) { s.Add(5); s.finish() }, 5, 0, 0, Done},
+		{"first finish wins", 10, func(s *State) { s.finish(); s.Add(10); s.finish() }, 10, 10, 1, Aborted},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewState(tt.total)
+			tt.update(s)
+			if s.Current() != tt.current || s.Total() != tt.wantTot {
+				t.Errorf("current, total = %d, %d, want %d, %d", s.Current(), s.Total(), tt.current, tt.wantTot)
+			}
+			if s.Percent() != tt.percent {
+				t.Errorf("percent = %v, want %v", s.Percent(), tt.percent)
+			}
+			if s.Status() != tt.status {
+				t.Errorf("status = %d, want %d", s.Status(), tt.status)
+			}
+		})
+	}
+}
+
+func TestStateConcurrentAdd(t *testing.T) {
+	s := NewState(1000)
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 200 {
+				s.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	if s.Current() != 1000 {
+		t.Errorf("current = %d, want 1000", s.Current())
+	}
+}
+
+func TestStateLabels(t *testing.T) {
+	s := NewState(0)
+	s.SetLabel("file", "x.txt")
+	s.SetLabel("gone", "y")
+	s.SetLabel("gone", "")
+	snap := s.Snapshot()
+	if len(snap.Labels) != 1 || snap.Labels["file"] != "x.txt" || s.Label("gone") != "" {
+		t.Errorf("labels = %v, want map[file:x.txt]", snap.Labels)
+	}
+	snap.Labels["file"] = "changed"
+	if s.Label("file") != "x.txt" {
+		t.Error("the labels of the snapshot are shared with the state")
+	}
+	if !snap.StopTime.IsZero() || s.Elapsed() < 0 {
+		t.Errorf("a running state has the stop time %v", snap.StopTime)
+	}
+	s.finish()
+	if e := s.Elapsed(); e != s.StopTime().Sub(s.StartTime()) {
+		t.Errorf("elapsed = %v after the stop, want %v", e, s.StopTime().Sub(s.StartTime()))
+	}
+}
+
+func TestStateMessage(t *testing.T) {
+	s := NewState(0)
+	s.SetMessage("a\r\nb\nc\rd")
+	if snap := s.Snapshot(); snap.Message != "a b c d" || s.Message() != snap.Message {
+		t.Errorf("message = %q, want %q", snap.Message, "a b c d")
+	}
+}
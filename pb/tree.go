@@ -0,0 +1,97 @@
+package pb
+
+import "errors"
+
+// indent is the indentation of the children under their parent.
+const indent = "  "
+
+// AddChild starts a child of the runner with the bar and the total n, like bar.Start(n),
+// and returns the runner of the child. If bar is nil, the bar of the parent is used.
+// The progress of the parent is derived from its children: each child takes a share of weight,
+// or of its total if weight <= 0, so by default the parent counts the sum of the children.
+// The children are printed under the parent with indentation, a stopped child is collapsed to its own lines,
+// or hidden if the parent has the HideFinished property.
+// Updating the progress of a parent with children directly has no effect.
+func (r *Runner) AddChild(bar *ProgressBar, n int, weight int64) (*Runner, error) {
+	if bar == nil {
+		bar = r.bar
+	}
+	if bar.property.FullScreen || bar.property.BindPos {
+		return nil, errors.New("a child bar cannot be full-screen or bound to a position")
+	}
+	child, err := bar.Start(n)
+	if err != nil {
+		return nil, err
+	}
+	child.ctx.parent = r.ctx
+	child.ctx.weight = max(weight, 0)
+
+	r.ctx.mu.Lock()
+	child.ctx.WindowWidth = max(r.ctx.WindowWidth-len(indent), 0)
+	if child.ctx.Property.Width > child.ctx.WindowWidth {
+		child.ctx.Property.Width = child.ctx.WindowWidth
+	}
+	r.ctx.children = append(r.ctx.children, child.ctx)
+	r.ctx.mu.Unlock()
+
+	child.ctx.Print()
+	return child, nil
+}
+
+// weightScale is the units of the progress of a parent per unit of the share of a child, when a child has a weight,
+// so that a child with a relative weight such as 1 moves its parent smoothly before it completes.
+const weightScale = 1000
+
+// aggregate derives the progress of a parent from its children, ctx.mu must be held.
+// A child takes a share of its weight, or of its total if the weight is 0,
+// a stopped child with an unknown total counts as complete.
+func (ctx *Context) aggregate() {
+	if len(ctx.children) == 0 {
+		return
+	}
+	scale := int64(1) // the parent counts the sum of the children, unless a child has a weight
+	for _, c := range ctx.children {
+		if c.weight > 0 {
+			scale = weightScale
+			break
+		}
+	}
+	var cur, total int64
+	for _, c := range ctx.children {
+		n, t := c.State.Current(), c.State.Total()
+		share := c.weight
+		if share == 0 {
+			share = t
+		}
+		share *= scale
+		total += share
+		switch {
+		case t > 0:
+			cur += int64(float64(share) * float64(min(n, t)) / float64(t))
+		case c.State.Status() != Running:
+			cur += share
+		}
+	}
+	ctx.State.SetTotal(total)
+	ctx.State.SetCurrent(cur)
+}
+
+// childLines returns the indented lines of the children, ctx.mu must be held.
+func (ctx *Context) childLines() []string {
+	var lines []string
+	for _, c := range ctx.children {
+		c.mu.Lock()
+		cl := c.lines
+		if c.State.Status() != Running {
+			cl = cl[:min(c.own, len(cl))] // collapsed
+			if ctx.Property.HideFinished {
+				cl = nil
+			}
+		}
+		for _, line := range cl {
+			lines = append(lines, indent+line)
+		}
+		c.mu.Unlock()
+	}
+	return lines
+}
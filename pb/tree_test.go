@@ -0,0 +1,41 @@
+package pb
+
+import "testing"
+
+// child returns a context of a child with the progress n of total t and the weight.
+func child(n, t, weight int64) *Context {
+	s := NewState(t)
+	s.SetCurrent(n)
+	return &Context{State: s, weight: weight}
+}
+
+func TestAggregate(t *testing.T) {
+	tests := []struct {
+		name     string
+		children []*Context
+		percent  float64
+	}{
+		{"weights of 1 at half", []*Context{child(50, 100, 1), child(5, 10, 1), child(1, 2, 1)}, 0.5},
+		{"weights of 1 before the first step", []*Context{child(1, 3, 1), child(0, 7, 1), child(0, 7, 1)}, 1.0 / 9},
+		{"weights by total", []*Context{child(10, 10, 0), child(0, 30, 0)}, 0.25},
+		{"relative weights", []*Context{child(10, 10, 3), child(0, 10, 1)}, 0.75},
+		{"weight and total mixed", []*Context{child(1, 2, 2), child(2, 2, 0)}, 0.75},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parent := &Context{State: NewState(0), children: tt.children}
+			parent.aggregate()
+			if got := parent.State.Percent(); got < tt.percent-1e-3 || got > tt.percent+1e-3 {
+				t.Errorf("percent = %v, want %v", got, tt.percent)
+			}
+		})
+	}
+}
+
+func TestAggregateSum(t *testing.T) {
+	parent := &Context{State: NewState(0), children: []*Context{child(3, 10, 0), child(4, 20, 0)}}
+	parent.aggregate()
+	if cur, total := parent.State.Current(), parent.State.Total(); cur != 7 || total != 30 {
+		t.Errorf("progress = %d/%d, want 7/30", cur, total)
+	}
+}
@@ -0,0 +1,112 @@
+// Package spinner provides a catalog of spinner animations and a standalone Spinner shown in a live region.
+// Frames are selected by the elapsed time instead of the number of renders,
+// so a spinner turns at the same speed however often it is redrawn.
+package spinner
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gngtwhh/gocui/font"
+)
+
+// Frames is a named set of frames played in a loop, one frame per Interval.
+type Frames struct {
+	Frames   []string
+	Interval time.Duration
+}
+
+// The builtin frame sets
+var (
+	Dots    = Frames{[]string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}, 80 * time.Millisecond}
+	Dots2   = Frames{[]string{"⣾", "⣽", "⣻", "⢿", "⡿", "⣟", "⣯", "⣷"}, 80 * time.Millisecond}
+	Line    = Frames{[]string{"-", "\\", "|", "/"}, 130 * time.Millisecond}
+	Pipe    = Frames{[]string{"┤", "┘", "┴", "└", "├", "┌", "┬", "┐"}, 100 * time.Millisecond}
+	Arc     = Frames{[]string{"◜", "◠", "◝", "◞", "◡", "◟"}, 100 * time.Millisecond}
+	Circle  = Frames{[]string{"◐", "◓", "◑", "◒"}, 120 * time.Millisecond}
+	Square  = Frames{[]string{"◰", "◳", "◲", "◱"}, 120 * time.Millisecond}
+	Bounce  = Frames{[]string{"⠁", "⠂", "⠄", "⠂"}, 120 * time.Millisecond}
+	Toggle  = Frames{[]string{"⊶", "⊷"}, 250 * time.Millisecond}
+	Arrow   = Frames{[]string{"←", "↖", "↑", "↗", "→", "↘", "↓", "↙"}, 100 * time.Millisecond}
+	Pulse   = Frames{[]string{"█", "▓", "▒", "░", "▒", "▓"}, 120 * time.Millisecond}
+	Grow    = Frames{[]string{"▁", "▃", "▄", "▅", "▆", "▇", "█", "▇", "▆", "▅", "▄", "▃"}, 100 * time.Millisecond}
+	Ball    = Frames{[]string{"( ●    )", "(  ●   )", "(   ●  )", "(    ● )", "(     ●)", "(    ● )", "(   ●  )", "(  ●   )", "( ●    )", "(●     )"}, 80 * time.Millisecond}
+	Clock   = Frames{[]string{"🕛", "🕐", "🕑", "🕒", "🕓", "🕔", "🕕", "🕖", "🕗", "🕘", "🕙", "🕚"}, 100 * time.Millisecond}
+	Moon    = Frames{[]string{"🌑", "🌒", "🌓", "🌔", "🌕", "🌖", "🌗", "🌘"}, 80 * time.Millisecond}
+	Earth   = Frames{[]string{"🌍", "🌎", "🌏"}, 180 * time.Millisecond}
+	Hearts  = Frames{[]string{"💛", "💙", "💜", "💚", "❤️"}, 100 * time.Millisecond}
+	Snake   = Frames{[]string{"⠧⠤⠴", "⠯⠥⠄", "⠯⠍⠁", "⠏⠉⠙", "⠉⠉⠽", "⠀⠭⠽", "⠤⠤⠽"}, 80 * time.Millisecond}
+	Default = Dots
+)
+
+var (
+	mu   sync.RWMutex
+	sets = map[string]Frames{
+		"dots":   Dots,
+		"dots2":  Dots2,
+		"line":   Line,
+		"pipe":   Pipe,
+		"arc":    Arc,
+		"circle": Circle,
+		"square": Square,
+		"bounce": Bounce,
+		"toggle": Toggle,
+		"arrow":  Arrow,
+		"pulse":  Pulse,
+		"grow":   Grow,
+		"ball":   Ball,
+		"clock":  Clock,
+		"moon":   Moon,
+		"earth":  Earth,
+		"hearts": Hearts,
+		"snake":  Snake,
+	}
+)
+
+// Get returns the frame set registered with the name.
+func Get(name string) (f Frames, ok bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	f, ok = sets[name]
+	return
+}
+
+// Register registers a frame set with the name, replacing the set with the same name.
+func Register(name string, f Frames) {
+	mu.Lock()
+	defer mu.Unlock()
+	sets[name] = f
+}
+
+// Names returns the sorted names of the registered frame sets.
+func Names() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	names := make([]string, 0, len(sets))
+	for name := range sets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// At returns the frame shown after elapsed time, or "" if there are no frames.
+func (f Frames) At(elapsed time.Duration) string {
+	if len(f.Frames) == 0 {
+		return ""
+	}
+	if f.Interval <= 0 || elapsed < 0 {
+		return f.Frames[0]
+	}
+	return f.Frames[int(elapsed/f.Interval)%len(f.Frames)]
+}
+
+// Width returns the display width of the widest frame.
+func (f Frames) Width() int {
+	w := 0
+	for _, frame := range f.Frames {
+		w = max(w, font.Width(frame))
+	}
+	return w
+}
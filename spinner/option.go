@@ -0,0 +1,55 @@
+package spinner
+
+import "time"
+
+// ModFunc is a function that modifies the Property of the spinner.
+type ModFunc func(p *Property)
+
+// Property is the property of the spinner.
+type Property struct {
+	Frames        Frames        // Frames: the animation, default Dots
+	Colors        []int         // Colors: the colors cycled by the frame, default none
+	ColorInterval time.Duration // ColorInterval: how long each color lasts, default the interval of the frames
+	Text          string        // Text: the text after the frame
+	SuccessMark   string        // SuccessMark: the mark replacing the frame on Success, default a green "✔"
+	FailMark      string        // FailMark: the mark replacing the frame on Fail, default a red "✖"
+}
+
+// WithFrames sets the animation to the registered frame set with the name, unknown names are ignored.
+func WithFrames(name string) ModFunc {
+	return func(p *Property) {
+		if f, ok := Get(name); ok {
+			p.Frames = f
+		}
+	}
+}
+
+// WithCustomFrames sets the animation to the given frames.
+func WithCustomFrames(f Frames) ModFunc {
+	return func(p *Property) {
+		p.Frames = f
+	}
+}
+
+// WithColors sets the colors cycled by the frame, each lasting interval, 0 for the interval of the frames.
+func WithColors(interval time.Duration, colors ...int) ModFunc {
+	return func(p *Property) {
+		p.Colors = colors
+		p.ColorInterval = interval
+	}
+}
+
+// WithText sets the text after the frame.
+func WithText(text string) ModFunc {
+	return func(p *Property) {
+		p.Text = text
+	}
+}
+
+// WithMarks sets the marks replacing the frame on Success and Fail.
+func WithMarks(success, fail string) ModFunc {
+	return func(p *Property) {
+		p.SuccessMark = success
+		p.FailMark = fail
+	}
+}
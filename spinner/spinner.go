@@ -0,0 +1,171 @@
+package spinner
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/gngtwhh/gocui/font"
+	"github.com/gngtwhh/gocui/live"
+	"github.com/gngtwhh/gocui/widget"
+)
+
+// Spinner is an animated frame followed by a text.
+// Started, it spins in a live region below the cursor, it can also be rendered as a widget.
+type Spinner struct {
+	p      Property
+	text   string
+	start  time.Time
+	region *live.Region
+	stop   chan struct{}
+	done   chan struct{}
+	mu     sync.Mutex
+}
+
+// New creates a spinner with several modify functions.
+func New(mfs ...ModFunc) (*Spinner, error) {
+	p := Property{
+		Frames:      Default,
+		SuccessMark: font.Decorate("✔", font.Green),
+		FailMark:    font.Decorate("✖", font.Red),
+	}
+	for _, mf := range mfs {
+		if mf == nil {
+			return nil, errors.New("modify func cannot be nil")
+		}
+		mf(&p)
+	}
+	if len(p.Frames.Frames) == 0 {
+		return nil, errors.New("frames cannot be empty")
+	}
+	if p.Frames.Interval <= 0 {
+		p.Frames.Interval = Default.Interval
+	}
+	if p.ColorInterval <= 0 {
+		p.ColorInterval = p.Frames.Interval
+	}
+	return &Spinner{p: p, text: p.Text, start: time.Now()}, nil
+}
+
+// Start starts spinning in a live region reserved at the line of the cursor.
+// Calling Start on a running spinner does nothing.
+func (s *Spinner) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.region != nil {
+		return
+	}
+	s.start = time.Now()
+	s.region = live.New(1)
+	s.stop, s.done = make(chan struct{}), make(chan struct{})
+	s.region.Set(s.line(0))
+	go s.spin(s.region, s.stop, s.done)
+}
+
+// spin redraws the region each interval of the frames until stop is closed.
+func (s *Spinner) spin(region *live.Region, stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+	ticker := time.NewTicker(min(s.p.Frames.Interval, s.p.ColorInterval))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			s.mu.Lock()
+			line := s.line(time.Since(s.start))
+			s.mu.Unlock()
+			region.Set(line)
+		}
+	}
+}
+
+// halt stops the animation and returns the live region, or nil if the spinner is not running.
+func (s *Spinner) halt() *live.Region {
+	s.mu.Lock()
+	region := s.region
+	if region == nil {
+		s.mu.Unlock()
+		return nil
+	}
+	s.region = nil
+	close(s.stop)
+	s.mu.Unlock()
+	<-s.done
+	return region
+}
+
+// Stop stops the spinner and removes its line.
+func (s *Spinner) Stop() {
+	if region := s.halt(); region != nil {
+		region.Remove()
+	}
+}
+
+// Success stops the spinner, printing the success mark followed by text, or the current text if text is empty.
+// The line is printed above the other active live regions.
+func (s *Spinner) Success(text string) {
+	s.finish(s.p.SuccessMark, text)
+}
+
+// Fail stops the spinner, printing the fail mark followed by text, or the current text if text is empty.
+func (s *Spinner) Fail(text string) {
+	s.finish(s.p.FailMark, text)
+}
+
+// finish stops the spinner, printing the mark followed by the text.
+func (s *Spinner) finish(mark, text string) {
+	region := s.halt()
+	if region == nil {
+		return
+	}
+	s.mu.Lock()
+	if text == "" {
+		text = s.text
+	}
+	s.mu.Unlock()
+	region.Remove()
+	live.Print(mark + " " + text + "\n") // a terminated line, not overwritten by the next spinner
+}
+
+// SetText replaces the text after the frame.
+func (s *Spinner) SetText(text string) {
+	s.mu.Lock()
+	s.text = text
+	region, line := s.region, s.line(time.Since(s.start))
+	s.mu.Unlock()
+	if region != nil {
+		region.Set(line)
+	}
+}
+
+// Measure implements widget.Widget
+func (s *Spinner) Measure(c widget.Constraints) widget.Size {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cols := s.p.Frames.Width()
+	if s.text != "" {
+		cols += 1 + font.Width(s.text)
+	}
+	return c.Fit(widget.Size{Rows: 1, Cols: cols})
+}
+
+// Render implements widget.Widget, drawing the frame of the time since the spinner was created or started.
+func (s *Spinner) Render(r widget.Region) {
+	s.mu.Lock()
+	line := s.line(time.Since(s.start))
+	s.mu.Unlock()
+	r.Line(0, line)
+}
+
+// line returns the frame after elapsed time followed by the text, the caller must hold s.mu.
+func (s *Spinner) line(elapsed time.Duration) string {
+	frame := font.Pad(s.p.Frames.At(elapsed), s.p.Frames.Width()) // the text stays in place with frames of any width
+	if n := len(s.p.Colors); n > 0 {
+		frame = font.Decorate(frame, s.p.Colors[int(elapsed/s.p.ColorInterval)%n])
+	}
+	if s.text == "" {
+		return frame
+	}
+	return frame + " " + s.text
+}
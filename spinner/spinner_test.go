@@ -0,0 +1,152 @@
+package spinner
+
+import (
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gngtwhh/gocui/font"
+	"github.com/gngtwhh/gocui/widget"
+)
+
+func TestFramesAt(t *testing.T) {
+	tests := []struct {
+		frames  Frames
+		elapsed time.Duration
+		want    string
+	}{
+		{Line, 0, "-"},
+		{Line, 129 * time.Millisecond, "-"},
+		{Line, 130 * time.Millisecond, "\\"},
+		{Line, 3 * 130 * time.Millisecond, "/"},
+		{Line, 4 * 130 * time.Millisecond, "-"},
+		{Line, -time.Second, "-"},
+		{Frames{Frames: []string{"a", "b"}}, time.Hour, "a"},
+		{Frames{}, time.Second, ""},
+	}
+	for _, tt := range tests {
+		if got := tt.frames.At(tt.elapsed); got != tt.want {
+			t.Errorf("%q.At(%v) = %q, want %q", tt.frames.Frames, tt.elapsed, got, tt.want)
+		}
+	}
+}
+
+func TestRegister(t *testing.T) {
+	if f, ok := Get("line"); !ok || !slices.Equal(f.Frames, Line.Frames) {
+		t.Errorf("Get(line) = %v, %v", f, ok)
+	}
+	if _, ok := Get("missing"); ok {
+		t.Error("Get(missing) found a frame set")
+	}
+	Register("test", Frames{Frames: []string{"x"}, Interval: time.Second})
+	if f, ok := Get("test"); !ok || f.Frames[0] != "x" {
+		t.Errorf("Get(test) = %v, %v after Register", f, ok)
+	}
+	names := Names()
+	if !slices.IsSorted(names) || !slices.Contains(names, "test") || !slices.Contains(names, "dots") {
+		t.Errorf("Names() = %q", names)
+	}
+}
+
+func TestNew(t *testing.T) {
+	if _, err := New(nil); err == nil {
+		t.Error("no error for a nil modify func")
+	}
+	if _, err := New(WithCustomFrames(Frames{})); err == nil {
+		t.Error("no error for empty frames")
+	}
+	s, err := New(WithCustomFrames(Frames{Frames: []string{"a", "b"}}), WithText("loading"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.p.Frames.Interval != Default.Interval {
+		t.Errorf("interval = %v, want the default %v", s.p.Frames.Interval, Default.Interval)
+	}
+}
+
+func TestLine(t *testing.T) {
+	frames := Frames{Frames: []string{"a", "b"}, Interval: 100 * time.Millisecond}
+	tests := []struct {
+		name    string
+		mfs     []ModFunc
+		elapsed time.Duration
+		want    string
+	}{
+		{"frame", nil, 0, "a"},
+		{"next frame", nil, 150 * time.Millisecond, "b"},
+		{"text", []ModFunc{WithText("wait")}, 0, "a wait"},
+		{"colors", []ModFunc{WithColors(200*time.Millisecond, font.Red, font.Blue)}, 250 * time.Millisecond,
+			font.Decorate("a", font.Blue)},
+	}
+	for _, tt := range tests {
+		s, err := New(append([]ModFunc{WithCustomFrames(frames)}, tt.mfs...)...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := s.line(tt.elapsed); got != tt.want {
+			t.Errorf("%s: line = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestRender(t *testing.T) {
+	s, _ := New(WithCustomFrames(Frames{Frames: []string{"*"}, Interval: time.Second}), WithText("text"))
+	buf := widget.NewBuffer(1, 8)
+	s.Render(buf.Region())
+	if got := buf.Line(0); got != "* text  " {
+		t.Errorf("rendered %q, want %q", got, "* text  ")
+	}
+}
+
+func TestSuccess(t *testing.T) {
+	s, _ := New(WithCustomFrames(Frames{Frames: []string{"*"}, Interval: time.Millisecond}), WithText("working"),
+		WithMarks("ok", "ko"))
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+	out := make(chan string)
+	go func() {
+		b, _ := io.ReadAll(r)
+		out <- string(b)
+	}()
+	s.Start()
+	s.Start() // already running
+	time.Sleep(5 * time.Millisecond)
+	s.SetText("done")
+	s.Success("")
+	s.Fail("not running") // already stopped
+	w.Close()
+	got := <-out
+	if !strings.Contains(got, "* working") || !strings.HasSuffix(got, "ok done\n") {
+		t.Errorf("printed %q, want the spinner then %q", got, "ok done\n")
+	}
+}
+
+func TestMeasure(t *testing.T) {
+	tests := []struct {
+		frames []string
+		text   string
+		want   widget.Size
+		line   string
+	}{
+		{[]string{"a", "bbb"}, "", widget.Size{Rows: 1, Cols: 3}, "a  "},
+		{[]string{"a", "bbb"}, "x", widget.Size{Rows: 1, Cols: 5}, "a   x"},
+		{[]string{"💛", "❤️"}, "x", widget.Size{Rows: 1, Cols: 4}, "💛 x"},
+	}
+	for _, tt := range tests {
+		s, _ := New(WithCustomFrames(Frames{Frames: tt.frames, Interval: time.Second}), WithText(tt.text))
+		if got := s.Measure(widget.Constraints{}); got != tt.want {
+			t.Errorf("Measure of %q = %+v, want %+v", tt.frames, got, tt.want)
+		}
+		if got := s.line(0); got != tt.line {
+			t.Errorf("line of %q = %q, want %q", tt.frames, got, tt.line)
+		}
+	}
+}
@@ -0,0 +1,204 @@
+package widget
+
+import (
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/gngtwhh/gocui/font"
+)
+
+// Buffer is an off-screen Surface made of cells.
+// Widgets render into it as they do on the screen, then its lines can be written to the terminal at once.
+type Buffer struct {
+	mu         sync.Mutex
+	rows, cols int
+	cells      [][]cell
+
+	// OnChange, if set, is called after each change of the buffer, without holding its lock.
+	OnChange func()
+}
+
+// cell is a terminal cell, a wide character occupies a cell followed by a continuation cell with width 0.
+type cell struct {
+	ch    string // the character with its combining marks, "" means a space
+	style string // the SGR sequences in effect
+	width int
+}
+
+// NewBuffer creates a buffer of rows x cols blank cells.
+func NewBuffer(rows, cols int) *Buffer {
+	b := &Buffer{}
+	b.Resize(rows, cols)
+	return b
+}
+
+// Size returns the size of the buffer.
+func (b *Buffer) Size() Size {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return Size{Rows: b.rows, Cols: b.cols}
+}
+
+// Region returns the region covering the whole buffer.
+func (b *Buffer) Region() Region {
+	s := b.Size()
+	return Region{Rows: s.Rows, Cols: s.Cols, Surface: b}
+}
+
+// Resize changes the size of the buffer, keeping the cells that still fit.
+func (b *Buffer) Resize(rows, cols int) {
+	b.mu.Lock()
+	rows, cols = max(rows, 0), max(cols, 0)
+	cells := make([][]cell, rows)
+	for i := range cells {
+		cells[i] = make([]cell, cols)
+		for j := range cells[i] {
+			cells[i][j].width = 1
+			if i < b.rows && j < b.cols {
+				cells[i][j] = b.cells[i][j]
+			}
+		}
+	}
+	b.rows, b.cols, b.cells = rows, cols, cells
+	b.mu.Unlock()
+	b.changed()
+}
+
+// Clear blanks all the cells.
+func (b *Buffer) Clear() {
+	b.mu.Lock()
+	for i := range b.cells {
+		for j := range b.cells[i] {
+			b.cells[i][j] = cell{width: 1}
+		}
+	}
+	b.mu.Unlock()
+	b.changed()
+}
+
+// DrawLine implements Surface.
+// The escape sequences in the text are interpreted: SGR sequences style the following cells, others are dropped.
+func (b *Buffer) DrawLine(x, y int, text string) {
+	b.mu.Lock()
+	if x < 0 || x >= b.rows {
+		b.mu.Unlock()
+		return
+	}
+	style := ""
+	for i := 0; i < len(text); {
+		if text[i] == '\033' {
+			n := max(font.EscapeLen(text[i:]), 1)
+			if seq := text[i : i+n]; strings.HasPrefix(seq, "\033[") && seq[n-1] == 'm' {
+				style = applySGR(style, seq)
+			}
+			i += n
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(text[i:])
+		i += size
+		w := font.RuneWidth(r)
+		if w == 0 { // combining marks join the previous cell
+			if y > 0 && y <= b.cols && r >= ' ' {
+				prev := y - 1
+				if b.cells[x][prev].width == 0 && prev > 0 {
+					prev--
+				}
+				b.cells[x][prev].ch += string(r)
+				// the emoji presentation widens a narrow character to two cells
+				if r == font.EmojiPresentation && prev == y-1 && b.cells[x][prev].width == 1 && y < b.cols {
+					b.cells[x][prev].width = 2
+					b.set(x, y, cell{style: b.cells[x][prev].style, width: 0})
+					y++
+				}
+			}
+			continue
+		}
+		if y >= 0 && y+w <= b.cols {
+			b.set(x, y, cell{ch: string(r), style: style, width: w})
+			if w == 2 {
+				b.set(x, y+1, cell{style: style, width: 0})
+			}
+		}
+		y += w
+	}
+	b.mu.Unlock()
+	b.changed()
+}
+
+// set sets a cell, blanking the halves of the wide characters it overwrites.
+func (b *Buffer) set(x, y int, c cell) {
+	old := b.cells[x][y]
+	if old.width == 0 && c.width != 0 && y > 0 {
+		b.cells[x][y-1] = cell{style: b.cells[x][y-1].style, width: 1}
+	}
+	if old.width == 2 && c.width != 2 && y+1 < b.cols {
+		b.cells[x][y+1] = cell{style: old.style, width: 1}
+	}
+	b.cells[x][y] = c
+}
+
+// Line returns the i-th line of the buffer with its styles.
+func (b *Buffer) Line(i int) string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if i < 0 || i >= b.rows {
+		return ""
+	}
+	return b.line(i)
+}
+
+// Lines returns all the lines of the buffer with their styles.
+func (b *Buffer) Lines() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	lines := make([]string, b.rows)
+	for i := range lines {
+		lines[i] = b.line(i)
+	}
+	return lines
+}
+
+func (b *Buffer) line(i int) string {
+	buf := strings.Builder{}
+	style := ""
+	for _, c := range b.cells[i] {
+		if c.width == 0 {
+			continue
+		}
+		if c.style != style {
+			if style != "" {
+				buf.WriteString("\033[0m")
+			}
+			buf.WriteString(c.style)
+			style = c.style
+		}
+		if c.ch == "" {
+			buf.WriteByte(' ')
+		} else {
+			buf.WriteString(c.ch)
+		}
+	}
+	if style != "" {
+		buf.WriteString("\033[0m")
+	}
+	return buf.String()
+}
+
+func (b *Buffer) changed() {
+	if b.OnChange != nil {
+		b.OnChange()
+	}
+}
+
+// applySGR returns the style after the SGR sequence seq is applied to style.
+func applySGR(style, seq string) string {
+	params := seq[2 : len(seq)-1]
+	if params == "" || params == "0" {
+		return ""
+	}
+	if rest, ok := strings.CutPrefix(params, "0;"); ok {
+		return "\033[" + rest + "m"
+	}
+	return style + seq
+}
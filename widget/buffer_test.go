@@ -0,0 +1,75 @@
+package widget
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestBufferDrawLine(t *testing.T) {
+	tests := []struct {
+		name  string
+		draws []draw
+		want  string
+	}{
+		{"plain", draws(0, "abc"), "abc   "},
+		{"offset", draws(2, "ab"), "  ab  "},
+		{"clipped", draws(4, "abcd"), "    ab"},
+		{"wide", draws(1, "北京"), " 北京 "},
+		{"wide not fitting", draws(5, "北"), "      "},
+		{"wide overwritten by half", append(draws(0, "北京"), draws(1, "x")...), " x京  "},
+		{"combining mark", draws(0, "éf"), "éf    "},
+		{"emoji presentation", draws(0, "❤️a"), "❤️a   "},
+		{"style", draws(1, "\033[31mab\033[0mc"), " \033[31mab\033[0mc  "},
+		{"style reset with params", draws(0, "\033[1ma\033[0;32mb"), "\033[1ma\033[0m\033[32mb\033[0m    "},
+		{"other escapes dropped", draws(0, "a\033[2Kb"), "ab    "},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewBuffer(1, 6)
+			for _, d := range tt.draws {
+				b.DrawLine(0, d.y, d.text)
+			}
+			if got := b.Line(0); got != tt.want {
+				t.Errorf("line = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// draw is a call of DrawLine on the first row.
+type draw struct {
+	y    int
+	text string
+}
+
+// draws returns a single draw of the text at the column y.
+func draws(y int, text string) []draw {
+	return []draw{{y, text}}
+}
+
+func TestBufferResizeAndRegion(t *testing.T) {
+	b := NewBuffer(2, 4)
+	changes := 0
+	b.OnChange = func() { changes++ }
+	r := b.Region()
+	r.Line(0, "abcdef")
+	r.Sub(1, 1, 1, 2).Line(0, "xyz")
+	if want := []string{"abcd", " xy "}; !slices.Equal(b.Lines(), want) {
+		t.Errorf("lines = %q, want %q", b.Lines(), want)
+	}
+	b.Resize(3, 2)
+	if want := []string{"ab", " x", "  "}; !slices.Equal(b.Lines(), want) {
+		t.Errorf("lines after resize = %q, want %q", b.Lines(), want)
+	}
+	b.Clear()
+	if want := []string{"  ", "  ", "  "}; !slices.Equal(b.Lines(), want) {
+		t.Errorf("lines after clear = %q, want %q", b.Lines(), want)
+	}
+	if changes != 4 {
+		t.Errorf("OnChange called %d times, want 4", changes)
+	}
+	b.DrawLine(5, 0, "out of the buffer")
+	if s := b.Size(); s.Rows != 3 || s.Cols != 2 {
+		t.Errorf("size = %+v, want 3 x 2", s)
+	}
+}
@@ -0,0 +1,29 @@
+package widget
+
+import "github.com/gngtwhh/gocui/font"
+
+// Text is a widget of static lines.
+type Text struct {
+	Lines []string
+	Color int // color of the text, 0 means no color
+}
+
+// Measure implements Widget
+func (t Text) Measure(c Constraints) Size {
+	s := Size{Rows: len(t.Lines)}
+	for _, line := range t.Lines {
+		s.Cols = max(s.Cols, font.Width(line))
+	}
+	return c.Fit(s)
+}
+
+// Render implements Widget
+func (t Text) Render(r Region) {
+	for i := 0; i < r.Rows && i < len(t.Lines); i++ {
+		line := font.Pad(t.Lines[i], r.Cols)
+		if t.Color != 0 {
+			line = font.Decorate(line, t.Color)
+		}
+		r.Line(i, line)
+	}
+}
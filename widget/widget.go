@@ -0,0 +1,117 @@
+package widget
+
+import (
+	"fmt"
+
+	"github.com/gngtwhh/gocui/cursor"
+	"github.com/gngtwhh/gocui/font"
+	"github.com/gngtwhh/gocui/utils"
+	"github.com/gngtwhh/gocui/window"
+)
+
+// Widget is the interface that all renderable components implement,
+// so that containers, layouts and the screen can treat them uniformly.
+type Widget interface {
+	// Measure returns the size the widget wants within the given constraints.
+	Measure(c Constraints) Size
+	// Render draws the widget into the region.
+	// It is called without holding utils.ConsoleMutex.
+	Render(r Region)
+}
+
+// Size is the size of a widget in terminal cells.
+type Size struct {
+	Rows, Cols int
+}
+
+// Constraints limits the size of a widget, 0 means unlimited.
+type Constraints struct {
+	MaxRows, MaxCols int
+}
+
+// Surface is where a Region is drawn.
+type Surface interface {
+	// DrawLine draws the text with its first cell at row x and column y.
+	DrawLine(x, y int, text string)
+}
+
+// Region is a rectangle of a Surface that a widget renders into.
+type Region struct {
+	X, Y       int     // the top left corner, X is the row and Y is the column as in cursor.GotoXY
+	Rows, Cols int     // the size of the region
+	Surface    Surface // where the region is drawn, nil means the terminal screen
+}
+
+// screen is the Surface of the terminal screen.
+type screen struct{}
+
+// Screen is the Surface of the terminal screen.
+var Screen Surface = screen{}
+
+// DrawLine implements Surface.
+// The cursor is moved back after drawing by cursor.Around, so the output at the cursor and the live regions
+// are not disturbed, the positions saved by cursor.Save are lost, see cursor.SaveExact.
+func (screen) DrawLine(x, y int, text string) {
+	utils.ConsoleMutex.Lock()
+	defer utils.ConsoleMutex.Unlock()
+	fmt.Print(cursor.Around(cursor.GotoXYSeq(x, y) + text))
+}
+
+// Fit shrinks the size to satisfy the constraints.
+func (c Constraints) Fit(s Size) Size {
+	if c.MaxRows > 0 {
+		s.Rows = min(s.Rows, c.MaxRows)
+	}
+	if c.MaxCols > 0 {
+		s.Cols = min(s.Cols, c.MaxCols)
+	}
+	return s
+}
+
+// Constraints returns the constraints that makes a widget fit in the region.
+func (r Region) Constraints() Constraints {
+	return Constraints{MaxRows: r.Rows, MaxCols: r.Cols}
+}
+
+// Size returns the size of the region.
+func (r Region) Size() Size {
+	return Size{Rows: r.Rows, Cols: r.Cols}
+}
+
+// Sub returns the part of the region at the relative position (x, y) with the given size,
+// clipped to the bounds of the region.
+func (r Region) Sub(x, y, rows, cols int) Region {
+	x, y = max(x, 0), max(y, 0)
+	rows = max(min(rows, r.Rows-x), 0)
+	cols = max(min(cols, r.Cols-y), 0)
+	return Region{X: r.X + x, Y: r.Y + y, Rows: rows, Cols: cols, Surface: r.Surface}
+}
+
+// Line draws the text as the i-th line of the region.
+// The text is truncated or padded with spaces to exactly the width of the region,
+// lines outside the region are ignored.
+func (r Region) Line(i int, text string) {
+	if i < 0 || i >= r.Rows || r.Cols <= 0 {
+		return
+	}
+	s := r.Surface
+	if s == nil {
+		s = Screen
+	}
+	s.DrawLine(r.X+i, r.Y, font.Pad(text, r.Cols))
+}
+
+// Clear fills the region with spaces.
+func (r Region) Clear() {
+	for i := 0; i < r.Rows; i++ {
+		r.Line(i, "")
+	}
+}
+
+// Print measures the widget against the terminal size and renders it on the screen at row x, column y.
+func Print(w Widget, x, y int) {
+	width, height := window.GetConsoleSize()
+	c := Constraints{MaxRows: max(height-x, 0), MaxCols: max(width-y, 0)}
+	s := c.Fit(w.Measure(c))
+	w.Render(Region{X: x, Y: y, Rows: s.Rows, Cols: s.Cols})
+}
@@ -0,0 +1,30 @@
+package window
+
+import (
+	"os"
+
+	"github.com/gngtwhh/gocui/internal/term"
+)
+
+// IsTerminal reports whether both stdin and stdout are terminals.
+func IsTerminal() bool {
+	return term.IsTerminal(int(os.Stdin.Fd())) && term.IsTerminal(int(os.Stdout.Fd()))
+}
+
+// MakeRaw puts the terminal into raw mode, so that every key is read as soon as it is pressed,
+// without echo and without generating signals.
+// It returns a function that restores the previous mode, which is also registered to Restore.
+func MakeRaw() (restore func() error, err error) {
+	fd := int(os.Stdin.Fd())
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return nil, err
+	}
+	unregister := RegisterRestore(func() {
+		_ = term.Restore(fd, state)
+	})
+	return func() error {
+		unregister()
+		return term.Restore(fd, state)
+	}, nil
+}
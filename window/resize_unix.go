@@ -0,0 +1,30 @@
+//go:build unix || darwin
+
+package window
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// OnResize calls f with the new size every time the terminal is resized, until stop is called.
+func OnResize(f func(width, height int)) (stop func()) {
+	ch := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(ch, syscall.SIGWINCH)
+	go func() {
+		for {
+			select {
+			case <-ch:
+				f(GetConsoleSize())
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() {
+		signal.Stop(ch)
+		close(done)
+	}
+}
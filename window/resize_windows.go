@@ -0,0 +1,33 @@
+//go:build windows
+
+package window
+
+import "time"
+
+// resizePollPeriod is the interval between two checks of the console size,
+// as Windows consoles do not send a signal when resized.
+const resizePollPeriod = time.Millisecond * 200
+
+// OnResize calls f with the new size every time the terminal is resized, until stop is called.
+func OnResize(f func(width, height int)) (stop func()) {
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(resizePollPeriod)
+		defer ticker.Stop()
+		w, h := GetConsoleSize()
+		for {
+			select {
+			case <-ticker.C:
+				if nw, nh := GetConsoleSize(); nw != w || nh != h {
+					w, h = nw, nh
+					f(w, h)
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() {
+		close(done)
+	}
+}
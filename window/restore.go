@@ -0,0 +1,124 @@
+package window
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/gngtwhh/gocui/cursor"
+	"github.com/gngtwhh/gocui/utils"
+)
+
+// restorer is a registered cleanup function
+type restorer struct {
+	f func()
+}
+
+// lockWait is how long Restore waits for utils.ConsoleMutex and the lock of the screen modes
+const lockWait = time.Millisecond * 100
+
+var (
+	restoreMu sync.Mutex
+	restorers []*restorer
+	restoring atomic.Bool // set while Restore runs the registered functions
+)
+
+// RegisterRestore registers a function that puts the terminal back in a sane state,
+// such as showing the cursor, leaving the alternate screen or restoring the terminal mode.
+// The functions are run by Restore in the reverse order of registration.
+// Call unregister once the cleanup has been done normally.
+func RegisterRestore(f func()) (unregister func()) {
+	r := &restorer{f: f}
+	restoreMu.Lock()
+	restorers = append(restorers, r)
+	restoreMu.Unlock()
+	return func() {
+		restoreMu.Lock()
+		defer restoreMu.Unlock()
+		for i, v := range restorers {
+			if v == r {
+				restorers = append(restorers[:i], restorers[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// Restore runs and removes all the registered functions, then resets the colors,
+// shows the cursor if hidden and restores its shape if changed.
+func Restore() {
+	restoreMu.Lock()
+	rs := restorers
+	restorers = nil
+	restoreMu.Unlock()
+	restoring.Store(true)
+	defer restoring.Store(false)
+	for i := len(rs) - 1; i >= 0; i-- {
+		rs[i].f()
+	}
+
+	// the console may be held by a goroutine that is being killed, do not wait for it forever
+	locked := tryLock(&utils.ConsoleMutex)
+	fmt.Print("\033[0m")
+	if cursor.Hidden() {
+		cursor.ShowCursor()
+	}
+	if cursor.Shape() != cursor.ShapeDefault {
+		cursor.SetShape(cursor.ShapeDefault)
+	}
+	if locked {
+		utils.ConsoleMutex.Unlock()
+	}
+}
+
+// tryLock locks mu unless it is still held by another goroutine after lockWait, and reports whether it did.
+func tryLock(mu *sync.Mutex) bool {
+	deadline := time.Now().Add(lockWait)
+	locked := mu.TryLock()
+	for !locked && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+		locked = mu.TryLock()
+	}
+	return locked
+}
+
+// Guard restores the terminal if the calling goroutine panics, then panics again with the same value.
+// It must be deferred directly: defer window.Guard()
+func Guard() {
+	if p := recover(); p != nil {
+		Restore()
+		panic(p)
+	}
+}
+
+// HandleSignals restores the terminal and exits when SIGINT or SIGTERM is received,
+// with the exit code 128 + the signal number as shells do. Call stop to remove the handlers.
+func HandleSignals() (stop func()) {
+	ch := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(ch, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		select {
+		case sig := <-ch:
+			Restore()
+			fmt.Println()
+			code := 1
+			if s, ok := sig.(syscall.Signal); ok {
+				code = 128 + int(s)
+			}
+			os.Exit(code)
+		case <-done:
+		}
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(ch)
+			close(done)
+		})
+	}
+}
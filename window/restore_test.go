@@ -0,0 +1,80 @@
+package window
+
+import (
+	"io"
+	"os"
+	"slices"
+	"testing"
+
+	"github.com/gngtwhh/gocui/cursor"
+)
+
+// capture returns what f prints on the standard output.
+func capture(t *testing.T, f func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	out := make(chan string)
+	go func() {
+		b, _ := io.ReadAll(r)
+		out <- string(b)
+	}()
+	defer func() {
+		os.Stdout = stdout
+	}()
+	f()
+	w.Close()
+	return <-out
+}
+
+func TestRestore(t *testing.T) {
+	var calls []string
+	RegisterRestore(func() { calls = append(calls, "first") })
+	unregister := RegisterRestore(func() { calls = append(calls, "removed") })
+	RegisterRestore(func() { calls = append(calls, "last") })
+	unregister()
+	unregister() // a second call does nothing
+
+	if out := capture(t, Restore); out != "\033[0m" {
+		t.Errorf("Restore printed %q, want %q", out, "\033[0m")
+	}
+	if want := []string{"last", "first"}; !slices.Equal(calls, want) {
+		t.Errorf("Restore called %q, want %q", calls, want)
+	}
+	calls = nil
+	capture(t, Restore)
+	if len(calls) != 0 {
+		t.Errorf("a second Restore called %q again", calls)
+	}
+}
+
+func TestRestoreShowsCursor(t *testing.T) {
+	capture(t, cursor.HideCursor)
+	if out := capture(t, Restore); out != "\033[0m\033[?25h" {
+		t.Errorf("Restore printed %q, want the cursor shown", out)
+	}
+	if cursor.Hidden() {
+		t.Error("the cursor is still hidden after Restore")
+	}
+}
+
+func TestGuard(t *testing.T) {
+	restored := false
+	RegisterRestore(func() { restored = true })
+	defer func() {
+		if p := recover(); p != "boom" {
+			t.Errorf("recovered %v, want the panic to go on", p)
+		}
+		if !restored {
+			t.Error("the terminal was not restored on panic")
+		}
+	}()
+	capture(t, func() {
+		defer Guard()
+		panic("boom")
+	})
+}
@@ -0,0 +1,190 @@
+package window
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/gngtwhh/gocui/cursor"
+)
+
+var (
+	screenMu         sync.Mutex // see lockScreen
+	altScreen        bool
+	unregisterAlt    func()
+	scrollRegion     bool
+	unregisterScroll func()
+	fullScreenCount  int // number of users of the full-screen mode
+	cursorWasHidden  bool
+)
+
+// lockScreen locks screenMu and returns the function unlocking it.
+// While Restore runs, it gives up after lockWait like Restore does for the console,
+// since the goroutine holding it may never release it, and unlock does nothing if it was not locked.
+func lockScreen() (unlock func()) {
+	if !restoring.Load() {
+		screenMu.Lock()
+		return screenMu.Unlock
+	}
+	if tryLock(&screenMu) {
+		return screenMu.Unlock
+	}
+	return func() {}
+}
+
+// EnterAltScreen switches to the alternate screen buffer (DECSET 1049),
+// so the content of the main screen and its scrollback are kept untouched.
+// The switch back is registered to Restore.
+func EnterAltScreen() {
+	unlock := lockScreen()
+	defer unlock()
+	enterAltScreen()
+}
+
+// ExitAltScreen switches back to the main screen buffer.
+func ExitAltScreen() {
+	unlock := lockScreen()
+	defer unlock()
+	exitAltScreen()
+}
+
+// InAltScreen reports whether the alternate screen buffer is in use.
+func InAltScreen() bool {
+	unlock := lockScreen()
+	defer unlock()
+	return altScreen
+}
+
+func enterAltScreen() {
+	if altScreen {
+		return
+	}
+	altScreen = true
+	fmt.Print("\033[?1049h")
+	unregisterAlt = RegisterRestore(func() {
+		unlock := lockScreen()
+		defer unlock()
+		fullScreenCount = 0 // the full-screen mode is on the alternate screen
+		exitAltScreen()
+	})
+}
+
+func exitAltScreen() {
+	if !altScreen {
+		return
+	}
+	altScreen = false
+	unregisterAlt()
+	fmt.Print("\033[?1049l")
+}
+
+// SetScrollRegion limits scrolling to the rows from top to bottom (DECSTBM), both included and counted from 0,
+// so the rows outside, like a status bar, stay in place while the content inside scrolls.
+// The cursor is moved to the top left corner of the screen. The reset is registered to Restore.
+func SetScrollRegion(top, bottom int) {
+	unlock := lockScreen()
+	defer unlock()
+	fmt.Printf("\033[%d;%dr", top+1, bottom+1)
+	if !scrollRegion {
+		scrollRegion = true
+		unregisterScroll = RegisterRestore(func() {
+			unlock := lockScreen()
+			defer unlock()
+			resetScrollRegion()
+		})
+	}
+}
+
+// ResetScrollRegion makes the whole screen scroll again.
+func ResetScrollRegion() {
+	unlock := lockScreen()
+	defer unlock()
+	resetScrollRegion()
+}
+
+func resetScrollRegion() {
+	fmt.Print("\033[r")
+	if scrollRegion {
+		scrollRegion = false
+		unregisterScroll()
+	}
+}
+
+// ScrollUp scrolls the content of the scroll region up by n lines, new blank lines appear at the bottom.
+func ScrollUp(n int) {
+	fmt.Printf("\033[%dS", n)
+}
+
+// ScrollDown scrolls the content of the scroll region down by n lines, new blank lines appear at the top.
+func ScrollDown(n int) {
+	fmt.Printf("\033[%dT", n)
+}
+
+// InsertLines inserts n blank lines at the cursor line, the lines below are pushed down inside the scroll region.
+func InsertLines(n int) {
+	fmt.Printf("\033[%dL", n)
+}
+
+// DeleteLines deletes n lines from the cursor line, the lines below are pulled up inside the scroll region.
+func DeleteLines(n int) {
+	fmt.Printf("\033[%dM", n)
+}
+
+// EnterFullScreen switches to the full-screen mode: the alternate screen, cleared, with the cursor hidden.
+// Calls are counted, the mode is left when ExitFullScreen has been called as many times,
+// so several widgets can share the full screen.
+func EnterFullScreen() {
+	unlock := lockScreen()
+	defer unlock()
+	enterFullScreen()
+}
+
+// ExitFullScreen leaves the full-screen mode once every user has exited it.
+func ExitFullScreen() {
+	unlock := lockScreen()
+	defer unlock()
+	exitFullScreen()
+}
+
+// ToggleFullScreen enters the full-screen mode if not in it, otherwise leaves it whatever the number of users.
+func ToggleFullScreen() {
+	unlock := lockScreen()
+	defer unlock()
+	if fullScreenCount > 0 {
+		fullScreenCount = 1
+		exitFullScreen()
+	} else {
+		enterFullScreen()
+	}
+}
+
+func enterFullScreen() {
+	fullScreenCount++
+	if fullScreenCount > 1 {
+		return
+	}
+	cursorWasHidden = cursor.Hidden()
+	enterAltScreen()
+	ClearScreen()
+	cursor.HideCursor()
+}
+
+func exitFullScreen() {
+	if fullScreenCount == 0 {
+		return
+	}
+	fullScreenCount--
+	if fullScreenCount > 0 {
+		return
+	}
+	if !cursorWasHidden {
+		cursor.ShowCursor()
+	}
+	exitAltScreen()
+}
+
+// IsFullScreen reports whether the full-screen mode is on.
+func IsFullScreen() bool {
+	unlock := lockScreen()
+	defer unlock()
+	return fullScreenCount > 0
+}
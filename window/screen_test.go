@@ -0,0 +1,102 @@
+package window
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gngtwhh/gocui/cursor"
+)
+
+func TestAltScreen(t *testing.T) {
+	tests := []struct {
+		name string
+		f    func()
+		want string
+		in   bool
+	}{
+		{"enter", EnterAltScreen, "\033[?1049h", true},
+		{"enter again", EnterAltScreen, "", true},
+		{"exit", ExitAltScreen, "\033[?1049l", false},
+		{"exit again", ExitAltScreen, "", false},
+	}
+	for _, tt := range tests {
+		if out := capture(t, tt.f); out != tt.want || InAltScreen() != tt.in {
+			t.Errorf("%s: printed %q, in the alternate screen %v, want %q, %v", tt.name, out, InAltScreen(), tt.want, tt.in)
+		}
+	}
+}
+
+func TestScrollRegion(t *testing.T) {
+	tests := []struct {
+		name string
+		f    func()
+		want string
+	}{
+		{"set", func() { SetScrollRegion(1, 10) }, "\033[2;11r"},
+		{"scroll up", func() { ScrollUp(2) }, "\033[2S"},
+		{"scroll down", func() { ScrollDown(3) }, "\033[3T"},
+		{"insert lines", func() { InsertLines(1) }, "\033[1L"},
+		{"delete lines", func() { DeleteLines(4) }, "\033[4M"},
+		{"reset", ResetScrollRegion, "\033[r"},
+		{"nothing to restore", Restore, "\033[0m"},
+	}
+	for _, tt := range tests {
+		if out := capture(t, tt.f); out != tt.want {
+			t.Errorf("%s: printed %q, want %q", tt.name, out, tt.want)
+		}
+	}
+}
+
+func TestFullScreen(t *testing.T) {
+	enter := "\033[?1049h\033[H\033[J\033[?25l"
+	exit := "\033[?25h\033[?1049l"
+	tests := []struct {
+		name string
+		f    func()
+		want string
+		on   bool
+	}{
+		{"enter", EnterFullScreen, enter, true},
+		{"enter by a second user", EnterFullScreen, "", true},
+		{"exit by the second user", ExitFullScreen, "", true},
+		{"exit by the first user", ExitFullScreen, exit, false},
+		{"exit again", ExitFullScreen, "", false},
+		{"toggle on", ToggleFullScreen, enter, true},
+		{"enter while toggled", EnterFullScreen, "", true},
+		{"toggle off whatever the users", ToggleFullScreen, exit, false},
+	}
+	for _, tt := range tests {
+		if out := capture(t, tt.f); out != tt.want || IsFullScreen() != tt.on {
+			t.Errorf("%s: printed %q, full screen %v, want %q, %v", tt.name, out, IsFullScreen(), tt.want, tt.on)
+		}
+	}
+	if InAltScreen() || cursor.Hidden() {
+		t.Error("the full-screen mode left the alternate screen or the hidden cursor behind")
+	}
+}
+
+func TestRestoreScreen(t *testing.T) {
+	capture(t, func() {
+		EnterFullScreen()
+		SetScrollRegion(0, 5)
+	})
+	// the screen is locked by a goroutine that never releases it, such as one killed by a signal
+	screenMu.Lock()
+	done := make(chan string)
+	go func() { done <- capture(t, Restore) }()
+	select {
+	case out := <-done:
+		if want := "\033[r\033[?1049l\033[0m\033[?25h"; out != want {
+			t.Errorf("Restore printed %q, want %q", out, want)
+		}
+	case <-time.After(10 * lockWait):
+		t.Fatal("Restore is blocked by the lock of the screen")
+	}
+	screenMu.Unlock()
+	if InAltScreen() || IsFullScreen() {
+		t.Error("the screen modes are still on after Restore")
+	}
+	if out := capture(t, ExitFullScreen); out != "" {
+		t.Errorf("ExitFullScreen after Restore printed %q", out)
+	}
+}
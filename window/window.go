@@ -20,9 +20,7 @@ func ClearLine(row int) {
 	if row < 0 {
 		fmt.Print("\033[2K")
 	} else {
-		fmt.Print("\033[s")
-		cursor.GotoXY(row, 0)
-		fmt.Print("\033[2K\033[u")
+		fmt.Print(cursor.Around(cursor.GotoXYSeq(row, 0) + "\033[2K"))
 	}
 }
 
@@ -36,6 +34,8 @@ func ClearLineBeforCursor() {
 	fmt.Print("\033[1K")
 }
 
+// ClearScreen clears the screen and moves the cursor to the top left corner.
+// On the main screen the previous content is lost from the view, use EnterAltScreen to keep it.
 func ClearScreen() {
 	fmt.Printf("%s", "\033[H\033[J")
 }